@@ -5,13 +5,38 @@ import (
 	"os"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/vitoraalmeida/service/app/services/sales-api/handlers/v1/categorygrp"
+	"github.com/vitoraalmeida/service/app/services/sales-api/handlers/v1/exchangegrp"
+	"github.com/vitoraalmeida/service/app/services/sales-api/handlers/v1/inventorygrp"
+	"github.com/vitoraalmeida/service/app/services/sales-api/handlers/v1/productgrp"
+	"github.com/vitoraalmeida/service/app/services/sales-api/handlers/v1/reportgrp"
+	"github.com/vitoraalmeida/service/app/services/sales-api/handlers/v1/salegrp"
+	"github.com/vitoraalmeida/service/app/services/sales-api/handlers/v1/searchgrp"
 	"github.com/vitoraalmeida/service/app/services/sales-api/handlers/v1/testgrp"
 	"github.com/vitoraalmeida/service/app/services/sales-api/handlers/v1/usergrp"
+	"github.com/vitoraalmeida/service/business/core/category"
+	"github.com/vitoraalmeida/service/business/core/category/stores/categorydb"
+	"github.com/vitoraalmeida/service/business/core/exchange"
+	"github.com/vitoraalmeida/service/business/core/exchange/stores/exchangedb"
+	"github.com/vitoraalmeida/service/business/core/inventory"
+	"github.com/vitoraalmeida/service/business/core/inventory/stores/inventorydb"
+	"github.com/vitoraalmeida/service/business/core/product"
+	"github.com/vitoraalmeida/service/business/core/product/stores/productdb"
+	"github.com/vitoraalmeida/service/business/core/sale"
+	"github.com/vitoraalmeida/service/business/core/sale/stores/saledb"
 	"github.com/vitoraalmeida/service/business/core/user"
 	"github.com/vitoraalmeida/service/business/core/user/stores/userdb"
+	"github.com/vitoraalmeida/service/business/cview/report"
+	"github.com/vitoraalmeida/service/business/cview/report/stores/reportdb"
+	"github.com/vitoraalmeida/service/business/cview/search"
+	"github.com/vitoraalmeida/service/business/cview/search/stores/searchdb"
+	"github.com/vitoraalmeida/service/business/cview/user/summary"
+	"github.com/vitoraalmeida/service/business/cview/user/summary/stores/summarydb"
 	"github.com/vitoraalmeida/service/business/web/auth"
 	"github.com/vitoraalmeida/service/business/web/v1/mid"
+	"github.com/vitoraalmeida/service/business/web/v1/paging"
 	"github.com/vitoraalmeida/service/foundation/web"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -21,6 +46,18 @@ type APIMuxConfig struct {
 	Log      *zap.SugaredLogger
 	Auth     *auth.Auth // Objeto que armazena informções referentes à autenticação
 	DB       *sqlx.DB
+	Tracer   trace.Tracer // cria os spans das requisições
+
+	// TraceIDHeader é o cabeçalho de onde o ID da requisição é lido, como
+	// X-Request-ID ou traceparent
+	TraceIDHeader string
+
+	// Logging define quais requisições são registradas nos logs
+	Logging mid.LoggerConfig
+
+	// SummaryMaterialized faz o resumo de usuários ser lido da cópia
+	// materializada, atualizada em segundo plano
+	SummaryMaterialized bool
 }
 
 // APIMux contrói um mux ( que implementa http.Handler) com todas as rotas
@@ -33,7 +70,7 @@ func APIMux(cfg APIMuxConfig) *web.App {
 	// ou seja, todo handler a ser executado ocorrerá depois de passar
 	// pelo middleware de logs e depois de erros, de forma que se o handler
 	// retornar um erro, será lidado pelo mid de erros
-	app := web.NewApp(cfg.Shutdown, mid.Logger(cfg.Log), mid.Errors(cfg.Log), mid.Metrics(), mid.Panics())
+	app := web.NewApp(cfg.Shutdown, cfg.Tracer, cfg.TraceIDHeader, mid.Logger(cfg.Log, cfg.Logging), mid.Errors(cfg.Log), mid.Metrics(), mid.Panics())
 
 	// Registra um handleFunc que irá prcessar requisições get em /test
 	app.Handle(http.MethodGet, "/test", testgrp.Test)
@@ -43,10 +80,92 @@ func APIMux(cfg APIMuxConfig) *web.App {
 	// -------------------------------------------------------------------------
 
 	usrCore := user.NewCore(userdb.NewStore(cfg.Log, cfg.DB))
+	smmStore := summarydb.NewStore(cfg.Log, cfg.DB)
+	if cfg.SummaryMaterialized {
+		smmStore = summarydb.NewMaterializedStore(cfg.Log, cfg.DB)
+	}
+	smmCore := summary.NewCore(smmStore)
+	exchCore := exchange.NewCore(cfg.Log, exchangedb.NewStore(cfg.Log, cfg.DB))
 
-	ugh := usergrp.New(usrCore)
+	ugh := usergrp.New(usrCore, smmCore, exchCore, cfg.Auth, paging.DefaultConfig)
 
 	app.Handle(http.MethodGet, "/users", ugh.Query)
+	app.Handle(http.MethodGet, "/users/:user_id", ugh.QueryByID, mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RuleAny))
+	app.Handle(http.MethodGet, "/usersummary", ugh.QuerySummary, mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RuleAdminOnly))
+	app.Handle(http.MethodGet, "/usersummary/export", ugh.ExportSummary, mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RuleAdminOnly))
+
+	// -------------------------------------------------------------------------
+
+	catCore := category.NewCore(cfg.Log, categorydb.NewStore(cfg.Log, cfg.DB))
+	prdCore := product.NewCore(cfg.Log, usrCore, catCore, exchCore, productdb.NewStore(cfg.Log, cfg.DB))
+
+	// cada grupo pode definir os próprios limites de paginação
+	pgh := productgrp.New(prdCore, exchCore, paging.Config{DefaultRows: 20, MinRows: 1, MaxRows: 200})
+
+	app.Handle(http.MethodGet, "/products", pgh.Query)
+	app.Handle(http.MethodGet, "/products/:product_id", pgh.QueryByID)
+	app.Handle(http.MethodGet, "/products/export", pgh.Export)
+	app.Handle(http.MethodPost, "/products/import", pgh.Import, mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RuleAny))
+
+	// -------------------------------------------------------------------------
+
+	cgh := categorygrp.New(catCore, paging.DefaultConfig)
+
+	// a árvore de categorias é mantida apenas por administradores
+	app.Handle(http.MethodGet, "/categories", cgh.Query, mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RuleAdminOnly))
+	app.Handle(http.MethodGet, "/categories/:category_id", cgh.QueryByID, mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RuleAdminOnly))
+	app.Handle(http.MethodPost, "/categories", cgh.Create, mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RuleAdminOnly))
+	app.Handle(http.MethodPut, "/categories/:category_id", cgh.Update, mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RuleAdminOnly))
+	app.Handle(http.MethodDelete, "/categories/:category_id", cgh.Delete, mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RuleAdminOnly))
+
+	// -------------------------------------------------------------------------
+
+	invCore := inventory.NewCore(cfg.Log, prdCore, inventorydb.NewStore(cfg.Log, cfg.DB))
+
+	igh := inventorygrp.New(invCore, prdCore, paging.DefaultConfig)
+
+	// alterações manuais de estoque ficam restritas a administradores
+	app.Handle(http.MethodGet, "/products/:product_id/movements", igh.QueryByProductID, mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RuleAny))
+	app.Handle(http.MethodPost, "/products/:product_id/movements", igh.Create, mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RuleAdminOnly))
+
+	// -------------------------------------------------------------------------
+
+	slCore := sale.NewCore(cfg.Log, prdCore, exchCore, saledb.NewStore(cfg.Log, cfg.DB))
+
+	sgh := salegrp.New(slCore, cfg.Auth, paging.DefaultConfig)
+
+	// vendas são registradas em nome do usuário do token
+	app.Handle(http.MethodPost, "/sales", sgh.Create, mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RuleAny))
+	app.Handle(http.MethodGet, "/sales", sgh.Query, mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RuleAny))
+	app.Handle(http.MethodGet, "/sales/:sale_id", sgh.QueryByID, mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RuleAny))
+
+	// -------------------------------------------------------------------------
+
+	egh := exchangegrp.New(exchCore)
+
+	// taxas de câmbio são mantidas apenas por administradores
+	app.Handle(http.MethodGet, "/exchangerates", egh.Query)
+	app.Handle(http.MethodPost, "/exchangerates/import", egh.Import, mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RuleAdminOnly))
+	app.Handle(http.MethodPut, "/exchangerates/:currency", egh.Set, mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RuleAdminOnly))
+	app.Handle(http.MethodDelete, "/exchangerates/:currency", egh.Delete, mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RuleAdminOnly))
+
+	// -------------------------------------------------------------------------
+
+	schCore := search.NewCore(searchdb.NewStore(cfg.Log, cfg.DB))
+
+	shh := searchgrp.New(schCore, cfg.Auth, paging.DefaultConfig)
+
+	// os usuários retornados dependem de quem faz a busca
+	app.Handle(http.MethodGet, "/search", shh.Query, mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RuleAny))
+
+	// -------------------------------------------------------------------------
+
+	rgh := reportgrp.New(report.NewCore(reportdb.NewStore(cfg.Log, cfg.DB)))
+
+	// relatórios gerenciais ficam restritos a administradores
+	app.Handle(http.MethodGet, "/reports/products/created", rgh.ProductsCreated, mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RuleAdminOnly))
+	app.Handle(http.MethodGet, "/reports/departments/cost", rgh.DepartmentCost, mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RuleAdminOnly))
+	app.Handle(http.MethodGet, "/reports/users/inventory", rgh.TopUsersByInventory, mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RuleAdminOnly))
 
 	// o objeto App implementa a internface http.Handler que é necessário para
 	// construir um http.Server
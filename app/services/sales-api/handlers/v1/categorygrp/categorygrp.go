@@ -0,0 +1,192 @@
+// Package categorygrp mantém o grupo de handlers para manutenção da árvore de
+// categorias de produtos.
+package categorygrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/core/category"
+	"github.com/vitoraalmeida/service/business/sys/validate"
+	v1 "github.com/vitoraalmeida/service/business/web/v1"
+	"github.com/vitoraalmeida/service/business/web/v1/paging"
+	"github.com/vitoraalmeida/service/foundation/web"
+)
+
+// Handlers gerencia o conjunto de endpoints de categorias
+type Handlers struct {
+	category *category.Core
+	paging   paging.Config
+}
+
+// New constrói os handlers para acesso às rotas
+func New(category *category.Core, pagingCfg paging.Config) *Handlers {
+	return &Handlers{
+		category: category,
+		paging:   pagingCfg,
+	}
+}
+
+// Create adiciona uma nova categoria
+func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var app AppNewCategory
+	if err := web.Decode(r, &app); err != nil {
+		if validate.IsFieldErrors(err) {
+			return err
+		}
+		return v1.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	nc, err := toCoreNewCategory(app)
+	if err != nil {
+		return v1.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	cat, err := h.category.Create(ctx, nc)
+	if err != nil {
+		if status, ok := errStatus(err); ok {
+			return v1.NewRequestError(err, status)
+		}
+		return fmt.Errorf("create: category[%+v]: %w", nc, err)
+	}
+
+	return web.Respond(ctx, w, toAppCategory(cat), http.StatusCreated)
+}
+
+// Update modifica o nome ou a posição de uma categoria na árvore
+func (h *Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id, err := uuid.Parse(web.Param(r, "category_id"))
+	if err != nil {
+		return validate.NewFieldsError("category_id", err)
+	}
+
+	var app AppUpdateCategory
+	if err := web.Decode(r, &app); err != nil {
+		if validate.IsFieldErrors(err) {
+			return err
+		}
+		return v1.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	uc, err := toCoreUpdateCategory(app)
+	if err != nil {
+		return v1.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	cat, err := h.category.QueryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, category.ErrNotFound) {
+			return v1.NewRequestError(err, http.StatusNotFound)
+		}
+		return fmt.Errorf("querybyid: id[%s]: %w", id, err)
+	}
+
+	cat, err = h.category.Update(ctx, cat, uc)
+	if err != nil {
+		if status, ok := errStatus(err); ok {
+			return v1.NewRequestError(err, status)
+		}
+		return fmt.Errorf("update: id[%s]: %w", id, err)
+	}
+
+	return web.Respond(ctx, w, toAppCategory(cat), http.StatusOK)
+}
+
+// Delete remove uma categoria sem subcategorias
+func (h *Handlers) Delete(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id, err := uuid.Parse(web.Param(r, "category_id"))
+	if err != nil {
+		return validate.NewFieldsError("category_id", err)
+	}
+
+	cat, err := h.category.QueryByID(ctx, id)
+	if err != nil {
+		switch {
+		// remover algo que não existe tem o mesmo efeito
+		case errors.Is(err, category.ErrNotFound):
+			return web.Respond(ctx, w, nil, http.StatusNoContent)
+		default:
+			return fmt.Errorf("querybyid: id[%s]: %w", id, err)
+		}
+	}
+
+	if err := h.category.Delete(ctx, cat); err != nil {
+		if status, ok := errStatus(err); ok {
+			return v1.NewRequestError(err, status)
+		}
+		return fmt.Errorf("delete: id[%s]: %w", id, err)
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// Query retorna uma lista de categorias paginada
+func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	page, err := paging.ParseRequest(r, h.paging)
+	if err != nil {
+		return err
+	}
+
+	orderBy, err := parseOrder(r)
+	if err != nil {
+		return err
+	}
+
+	cats, err := h.category.Query(ctx, orderBy, page.Number, page.RowsPerPage)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	items := make([]AppCategory, len(cats))
+	for i, cat := range cats {
+		items[i] = toAppCategory(cat)
+	}
+
+	total, err := h.category.Count(ctx)
+	if err != nil {
+		return fmt.Errorf("count: %w", err)
+	}
+
+	return web.Respond(ctx, w, paging.NewResponse(items, total, page.Number, page.RowsPerPage), http.StatusOK)
+}
+
+// QueryByID retorna uma categoria pelo seu ID
+func (h *Handlers) QueryByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id, err := uuid.Parse(web.Param(r, "category_id"))
+	if err != nil {
+		return validate.NewFieldsError("category_id", err)
+	}
+
+	cat, err := h.category.QueryByID(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, category.ErrNotFound):
+			return v1.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querybyid: id[%s]: %w", id, err)
+		}
+	}
+
+	return web.Respond(ctx, w, toAppCategory(cat), http.StatusOK)
+}
+
+// =============================================================================
+
+// errStatus retorna o status HTTP para os erros conhecidos do domínio
+func errStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, category.ErrUniqueName):
+		return http.StatusConflict, true
+	case errors.Is(err, category.ErrHasChildren):
+		return http.StatusConflict, true
+	case errors.Is(err, category.ErrCycle):
+		return http.StatusBadRequest, true
+	case errors.Is(err, category.ErrParentMissing):
+		return http.StatusBadRequest, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,107 @@
+package categorygrp
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/core/category"
+	"github.com/vitoraalmeida/service/business/sys/validate"
+)
+
+// AppCategory representa uma categoria no contexto de aplicação
+type AppCategory struct {
+	ID          string `json:"id"`
+	ParentID    string `json:"parentID,omitempty"`
+	Name        string `json:"name"`
+	DateCreated string `json:"dateCreated"`
+	DateUpdated string `json:"dateUpdated"`
+}
+
+// Converte uma categoria de domínio em categoria de aplicação
+func toAppCategory(cat category.Category) AppCategory {
+	var parentID string
+	if cat.ParentID != nil {
+		parentID = cat.ParentID.String()
+	}
+
+	return AppCategory{
+		ID:          cat.ID.String(),
+		ParentID:    parentID,
+		Name:        cat.Name,
+		DateCreated: cat.DateCreated.Format(time.RFC3339),
+		DateUpdated: cat.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+// =============================================================================
+
+// AppNewCategory contém os dados para criar uma categoria
+type AppNewCategory struct {
+	Name     string  `json:"name" validate:"required"`
+	ParentID *string `json:"parentID" validate:"omitempty,uuid4"`
+}
+
+// Valida se as informações passadas para criar a categoria são validas
+func (app AppNewCategory) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Converte os dados de aplicação no modelo de domínio
+func toCoreNewCategory(app AppNewCategory) (category.NewCategory, error) {
+	nc := category.NewCategory{
+		Name: app.Name,
+	}
+
+	if app.ParentID != nil {
+		parentID, err := uuid.Parse(*app.ParentID)
+		if err != nil {
+			return category.NewCategory{}, fmt.Errorf("parsing parentID: %w", err)
+		}
+		nc.ParentID = &parentID
+	}
+
+	return nc, nil
+}
+
+// =============================================================================
+
+// AppUpdateCategory contém os dados para atualizar uma categoria. Um parentID
+// vazio ("") move a categoria para a raiz
+type AppUpdateCategory struct {
+	Name     *string `json:"name" validate:"omitempty,min=1"`
+	ParentID *string `json:"parentID" validate:"omitempty,uuid4|len=0"`
+}
+
+// Valida se as informações passadas para atualizar a categoria são validas
+func (app AppUpdateCategory) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Converte os dados de aplicação no modelo de domínio
+func toCoreUpdateCategory(app AppUpdateCategory) (category.UpdateCategory, error) {
+	uc := category.UpdateCategory{
+		Name: app.Name,
+	}
+
+	if app.ParentID != nil {
+		if *app.ParentID == "" {
+			uc.MoveToRoot = true
+			return uc, nil
+		}
+
+		parentID, err := uuid.Parse(*app.ParentID)
+		if err != nil {
+			return category.UpdateCategory{}, fmt.Errorf("parsing parentID: %w", err)
+		}
+		uc.ParentID = &parentID
+	}
+
+	return uc, nil
+}
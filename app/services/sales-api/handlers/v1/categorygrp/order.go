@@ -0,0 +1,18 @@
+package categorygrp
+
+import (
+	"net/http"
+
+	"github.com/vitoraalmeida/service/business/core/category"
+	"github.com/vitoraalmeida/service/business/data/order"
+)
+
+// conjunto de todos os campos possíveis pelos quais podemos ordenar os resultados
+var orderByFields = map[string]struct{}{
+	category.OrderByID:   {},
+	category.OrderByName: {},
+}
+
+func parseOrder(r *http.Request) (order.By, error) {
+	return order.Parse(r, category.DefaultOrderBy, orderByFields)
+}
@@ -0,0 +1,101 @@
+// Package exchangegrp mantém o grupo de handlers para manutenção das taxas de
+// câmbio.
+package exchangegrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/vitoraalmeida/service/business/core/exchange"
+	"github.com/vitoraalmeida/service/business/data/money"
+	"github.com/vitoraalmeida/service/business/sys/validate"
+	v1 "github.com/vitoraalmeida/service/business/web/v1"
+	"github.com/vitoraalmeida/service/foundation/web"
+)
+
+// Handlers gerencia o conjunto de endpoints de câmbio
+type Handlers struct {
+	exchange *exchange.Core
+}
+
+// New constrói os handlers para acesso às rotas
+func New(exchange *exchange.Core) *Handlers {
+	return &Handlers{
+		exchange: exchange,
+	}
+}
+
+// Query retorna todas as taxas de câmbio cadastradas
+func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	rts, err := h.exchange.Query(ctx)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	return web.Respond(ctx, w, toAppRates(rts), http.StatusOK)
+}
+
+// Set cria ou atualiza a taxa de câmbio da moeda informada na rota
+func (h *Handlers) Set(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	currency := strings.ToUpper(web.Param(r, "currency"))
+	if err := money.ValidateCurrency(currency); err != nil {
+		return validate.NewFieldsError("currency", err)
+	}
+
+	var app AppSetRate
+	if err := web.Decode(r, &app); err != nil {
+		if validate.IsFieldErrors(err) {
+			return err
+		}
+		return v1.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	rt, err := h.exchange.Set(ctx, currency, app.Rate)
+	if err != nil {
+		switch {
+		case errors.Is(err, exchange.ErrBaseCurrency):
+			return v1.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("set: currency[%s]: %w", currency, err)
+		}
+	}
+
+	return web.Respond(ctx, w, toAppRate(rt), http.StatusOK)
+}
+
+// Import grava as taxas de câmbio enviadas em CSV no corpo da requisição, no
+// formato "currency,rate"
+func (h *Handlers) Import(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	rts, err := h.exchange.Import(ctx, r.Body)
+	if err != nil {
+		switch {
+		case validate.IsFieldErrors(err):
+			return err
+		default:
+			return fmt.Errorf("import: %w", err)
+		}
+	}
+
+	return web.Respond(ctx, w, toAppRates(rts), http.StatusOK)
+}
+
+// Delete remove a taxa de câmbio da moeda informada na rota
+func (h *Handlers) Delete(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	currency := strings.ToUpper(web.Param(r, "currency"))
+
+	if err := h.exchange.Delete(ctx, currency); err != nil {
+		switch {
+		case errors.Is(err, exchange.ErrBaseCurrency):
+			return v1.NewRequestError(err, http.StatusBadRequest)
+		case errors.Is(err, exchange.ErrInUse):
+			return v1.NewRequestError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("delete: currency[%s]: %w", currency, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
@@ -0,0 +1,47 @@
+package exchangegrp
+
+import (
+	"time"
+
+	"github.com/vitoraalmeida/service/business/core/exchange"
+	"github.com/vitoraalmeida/service/business/data/money"
+	"github.com/vitoraalmeida/service/business/sys/validate"
+)
+
+// AppRate representa uma taxa de câmbio no contexto de aplicação
+type AppRate struct {
+	Currency    string     `json:"currency"`
+	Rate        money.Rate `json:"rate"`
+	DateUpdated string     `json:"dateUpdated"`
+}
+
+// Converte uma taxa de domínio em taxa de aplicação
+func toAppRate(rt exchange.Rate) AppRate {
+	return AppRate{
+		Currency:    rt.Currency,
+		Rate:        rt.Rate,
+		DateUpdated: rt.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+// Converte um slice de taxas de domínio em taxas de aplicação
+func toAppRates(rts []exchange.Rate) []AppRate {
+	items := make([]AppRate, len(rts))
+	for i, rt := range rts {
+		items[i] = toAppRate(rt)
+	}
+	return items
+}
+
+// AppSetRate contém a taxa a ser definida para uma moeda
+type AppSetRate struct {
+	Rate money.Rate `json:"rate" validate:"required"`
+}
+
+// Valida se as informações passadas para definir a taxa são validas
+func (app AppSetRate) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Package inventorygrp mantém o grupo de handlers para o histórico de estoque
+// dos produtos.
+package inventorygrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/core/inventory"
+	"github.com/vitoraalmeida/service/business/core/product"
+	"github.com/vitoraalmeida/service/business/sys/validate"
+	"github.com/vitoraalmeida/service/business/web/auth"
+	v1 "github.com/vitoraalmeida/service/business/web/v1"
+	"github.com/vitoraalmeida/service/business/web/v1/paging"
+	"github.com/vitoraalmeida/service/foundation/web"
+)
+
+// Handlers gerencia o conjunto de endpoints de estoque
+type Handlers struct {
+	inventory *inventory.Core
+	product   *product.Core
+	paging    paging.Config
+}
+
+// New constrói os handlers para acesso às rotas
+func New(inventory *inventory.Core, product *product.Core, pagingCfg paging.Config) *Handlers {
+	return &Handlers{
+		inventory: inventory,
+		product:   product,
+		paging:    pagingCfg,
+	}
+}
+
+// Create registra uma movimentação de estoque para o produto em nome do
+// usuário autenticado
+func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	productID, err := uuid.Parse(web.Param(r, "product_id"))
+	if err != nil {
+		return validate.NewFieldsError("product_id", err)
+	}
+
+	var app AppNewMovement
+	if err := web.Decode(r, &app); err != nil {
+		if validate.IsFieldErrors(err) {
+			return err
+		}
+		return v1.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	userID, err := auth.GetUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	nm, err := toCoreNewMovement(productID, userID, app)
+	if err != nil {
+		return validate.NewFieldsError("kind", err)
+	}
+
+	mv, err := h.inventory.Record(ctx, nm)
+	if err != nil {
+		switch {
+		case errors.Is(err, product.ErrNotFound):
+			return v1.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, inventory.ErrInsufficientStock):
+			return v1.NewRequestError(err, http.StatusConflict)
+		case errors.Is(err, inventory.ErrInvalidQuantity):
+			return validate.NewFieldsError("quantity", err)
+		default:
+			return fmt.Errorf("record: movement[%+v]: %w", nm, err)
+		}
+	}
+
+	return web.Respond(ctx, w, toAppMovement(mv), http.StatusCreated)
+}
+
+// QueryByProductID retorna o histórico de movimentações de um produto,
+// paginado e das mais recentes para as mais antigas
+func (h *Handlers) QueryByProductID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	productID, err := uuid.Parse(web.Param(r, "product_id"))
+	if err != nil {
+		return validate.NewFieldsError("product_id", err)
+	}
+
+	page, err := paging.ParseRequest(r, h.paging)
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.product.QueryByID(ctx, productID); err != nil {
+		switch {
+		case errors.Is(err, product.ErrNotFound):
+			return v1.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querybyid: id[%s]: %w", productID, err)
+		}
+	}
+
+	mvs, err := h.inventory.QueryByProductID(ctx, productID, page.Number, page.RowsPerPage)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	items := make([]AppMovement, len(mvs))
+	for i, mv := range mvs {
+		items[i] = toAppMovement(mv)
+	}
+
+	total, err := h.inventory.CountByProductID(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("count: %w", err)
+	}
+
+	return web.Respond(ctx, w, paging.NewResponse(items, total, page.Number, page.RowsPerPage), http.StatusOK)
+}
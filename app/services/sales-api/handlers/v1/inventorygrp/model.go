@@ -0,0 +1,65 @@
+package inventorygrp
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/core/inventory"
+	"github.com/vitoraalmeida/service/business/sys/validate"
+)
+
+// AppMovement representa uma movimentação de estoque no contexto de aplicação
+type AppMovement struct {
+	ID          string `json:"id"`
+	ProductID   string `json:"productID"`
+	UserID      string `json:"userID"`
+	Kind        string `json:"kind"`
+	Quantity    int    `json:"quantity"`
+	Reason      string `json:"reason"`
+	DateCreated string `json:"dateCreated"`
+}
+
+// Converte uma movimentação de domínio em movimentação de aplicação
+func toAppMovement(mv inventory.Movement) AppMovement {
+	return AppMovement{
+		ID:          mv.ID.String(),
+		ProductID:   mv.ProductID.String(),
+		UserID:      mv.UserID.String(),
+		Kind:        mv.Kind.Name(),
+		Quantity:    mv.Quantity,
+		Reason:      mv.Reason,
+		DateCreated: mv.DateCreated.Format(time.RFC3339),
+	}
+}
+
+// AppNewMovement contém os dados para registrar uma movimentação. Vendas não
+// são aceitas aqui, pois são registradas pelas rotas de vendas
+type AppNewMovement struct {
+	Kind     string `json:"kind" validate:"required,oneof=RESTOCK ADJUSTMENT RETURN"`
+	Quantity int    `json:"quantity" validate:"required"`
+	Reason   string `json:"reason" validate:"required"`
+}
+
+// Valida se as informações passadas para registrar a movimentação são validas
+func (app AppNewMovement) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Converte os dados de aplicação no modelo de domínio
+func toCoreNewMovement(productID uuid.UUID, userID uuid.UUID, app AppNewMovement) (inventory.NewMovement, error) {
+	kind, err := inventory.ParseKind(app.Kind)
+	if err != nil {
+		return inventory.NewMovement{}, err
+	}
+
+	return inventory.NewMovement{
+		ProductID: productID,
+		UserID:    userID,
+		Kind:      kind,
+		Quantity:  app.Quantity,
+		Reason:    app.Reason,
+	}, nil
+}
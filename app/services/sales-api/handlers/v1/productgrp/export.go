@@ -0,0 +1,141 @@
+package productgrp
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vitoraalmeida/service/business/sys/validate"
+)
+
+// a cada exportFlushRows linhas o conteúdo é enviado ao cliente
+const exportFlushRows = 100
+
+// exportWriter escreve os produtos exportados num formato específico
+type exportWriter interface {
+	Write(app AppProduct) error
+	Flush() error
+}
+
+// exportFormat descreve um formato de exportação
+type exportFormat struct {
+	contentType string
+	filename    string
+	newWriter   func(w io.Writer) exportWriter
+}
+
+var exportFormats = map[string]exportFormat{
+	"csv": {
+		contentType: "text/csv",
+		filename:    "products.csv",
+		newWriter:   newCSVWriter,
+	},
+	"ndjson": {
+		contentType: "application/x-ndjson",
+		filename:    "products.ndjson",
+		newWriter:   newNDJSONWriter,
+	},
+}
+
+// parseExportFormat retorna o formato informado no parâmetro format. Por
+// padrão a exportação é em CSV
+func parseExportFormat(r *http.Request) (exportFormat, error) {
+	name := strings.ToLower(r.URL.Query().Get("format"))
+	if name == "" {
+		name = "csv"
+	}
+
+	format, exists := exportFormats[name]
+	if !exists {
+		return exportFormat{}, validate.NewFieldsError("format", fmt.Errorf("format %q must be csv or ndjson", name))
+	}
+
+	return format, nil
+}
+
+// =============================================================================
+
+// colunas do CSV exportado. As colunas aceitas na importação têm o mesmo nome
+var csvColumns = []string{"id", "user_id", "name", "cost", "currency", "quantity", "sold", "revenue", "category_id", "tags", "date_created", "date_updated"}
+
+// csvWriter escreve os produtos em CSV, com o cabeçalho na primeira linha
+type csvWriter struct {
+	cw     *csv.Writer
+	header bool
+}
+
+func newCSVWriter(w io.Writer) exportWriter {
+	return &csvWriter{
+		cw: csv.NewWriter(w),
+	}
+}
+
+// Write implementa exportWriter
+func (cw *csvWriter) Write(app AppProduct) error {
+	if !cw.header {
+		if err := cw.cw.Write(csvColumns); err != nil {
+			return err
+		}
+		cw.header = true
+	}
+
+	return cw.cw.Write([]string{
+		app.ID,
+		app.UserID,
+		app.Name,
+		app.Cost.String(),
+		app.Cost.Currency(),
+		strconv.Itoa(app.Quantity),
+		strconv.Itoa(app.Sold),
+		app.Revenue.String(),
+		app.CategoryID,
+		strings.Join(app.Tags, ","),
+		app.DateCreated,
+		app.DateUpdated,
+	})
+}
+
+// Flush implementa exportWriter. O cabeçalho é escrito mesmo sem produtos
+func (cw *csvWriter) Flush() error {
+	if !cw.header {
+		if err := cw.cw.Write(csvColumns); err != nil {
+			return err
+		}
+		cw.header = true
+	}
+
+	cw.cw.Flush()
+	return cw.cw.Error()
+}
+
+// =============================================================================
+
+// ndjsonWriter escreve os produtos em NDJSON, um objeto JSON por linha
+type ndjsonWriter struct {
+	bw  *bufio.Writer
+	enc *json.Encoder
+}
+
+func newNDJSONWriter(w io.Writer) exportWriter {
+	bw := bufio.NewWriter(w)
+
+	return &ndjsonWriter{
+		bw:  bw,
+		enc: json.NewEncoder(bw),
+	}
+}
+
+// Write implementa exportWriter
+func (nw *ndjsonWriter) Write(app AppProduct) error {
+	return nw.enc.Encode(app)
+}
+
+// Flush implementa exportWriter
+func (nw *ndjsonWriter) Flush() error {
+	return nw.bw.Flush()
+}
@@ -0,0 +1,89 @@
+package productgrp
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/core/product"
+	"github.com/vitoraalmeida/service/business/data/expr"
+	"github.com/vitoraalmeida/service/business/data/money"
+	"github.com/vitoraalmeida/service/business/sys/validate"
+)
+
+// parseCurrency retorna a moeda para a qual os valores devem ser convertidos,
+// informada no parâmetro currency. Retorna vazio se não informada
+func parseCurrency(r *http.Request) (string, error) {
+	currency := strings.ToUpper(r.URL.Query().Get("currency"))
+	if currency == "" {
+		return "", nil
+	}
+
+	if err := money.ValidateCurrency(currency); err != nil {
+		return "", validate.NewFieldsError("currency", err)
+	}
+
+	return currency, nil
+}
+
+// Verifica se a Query string contém campos que indicam filtros de resultados
+func parseFilter(r *http.Request) (product.QueryFilter, error) {
+	values := r.URL.Query()
+
+	var filter product.QueryFilter
+
+	if productID := values.Get("product_id"); productID != "" {
+		id, err := uuid.Parse(productID)
+		if err != nil {
+			return product.QueryFilter{}, validate.NewFieldsError("product_id", err)
+		}
+		filter.WithProductID(id)
+	}
+
+	if name := values.Get("name"); name != "" {
+		filter.WithName(name)
+	}
+
+	if cost := values.Get("cost"); cost != "" {
+		cst, err := money.Parse(cost, money.DefaultCurrency)
+		if err != nil {
+			return product.QueryFilter{}, validate.NewFieldsError("cost", err)
+		}
+		filter.WithCost(cst)
+	}
+
+	if quantity := values.Get("quantity"); quantity != "" {
+		qua, err := strconv.Atoi(quantity)
+		if err != nil {
+			return product.QueryFilter{}, validate.NewFieldsError("quantity", err)
+		}
+		filter.WithQuantity(qua)
+	}
+
+	if categoryID := values.Get("category_id"); categoryID != "" {
+		id, err := uuid.Parse(categoryID)
+		if err != nil {
+			return product.QueryFilter{}, validate.NewFieldsError("category_id", err)
+		}
+		filter.WithCategoryID(id)
+	}
+
+	// tags=a,b retorna produtos que possuem todas as tags
+	if tags := values.Get("tags"); tags != "" {
+		filter.WithTags(strings.Split(tags, ","))
+	}
+
+	// condições livres no formato filter=campo<op>valor,...
+	exp, err := expr.Parse(r, product.FilterFields)
+	if err != nil {
+		return product.QueryFilter{}, err
+	}
+	filter.WithExpression(exp)
+
+	if err := filter.Validate(); err != nil {
+		return product.QueryFilter{}, err
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,189 @@
+package productgrp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/core/product"
+	"github.com/vitoraalmeida/service/business/data/money"
+	"github.com/vitoraalmeida/service/business/sys/validate"
+	v1 "github.com/vitoraalmeida/service/business/web/v1"
+)
+
+// tamanho máximo de uma linha NDJSON
+const maxNDJSONLine = 1 << 20
+
+// parseImportMode retorna o modo de importação informado no parâmetro mode.
+// Por padrão a importação é atômica
+func parseImportMode(r *http.Request) (product.ImportMode, error) {
+	switch mode := r.URL.Query().Get("mode"); mode {
+	case "", "atomic":
+		return product.ImportAtomic, nil
+	case "tolerant":
+		return product.ImportTolerant, nil
+	default:
+		return 0, validate.NewFieldsError("mode", fmt.Errorf("mode %q must be atomic or tolerant", mode))
+	}
+}
+
+// newRowReader escolhe o leitor das linhas com base no Content-Type da
+// requisição. O corpo é lido conforme a importação avança
+func newRowReader(r *http.Request, userID uuid.UUID) (product.RowReader, error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, v1.NewRequestError(fmt.Errorf("content type: %w", err), http.StatusUnsupportedMediaType)
+	}
+
+	switch mediaType {
+	case "text/csv":
+		return newCSVReader(r.Body, userID)
+	case "application/x-ndjson", "application/ndjson":
+		return newNDJSONReader(r.Body, userID), nil
+	default:
+		return nil, v1.NewRequestError(fmt.Errorf("content type %q must be text/csv or application/x-ndjson", mediaType), http.StatusUnsupportedMediaType)
+	}
+}
+
+// =============================================================================
+
+// csvReader lê produtos de um CSV. A primeira linha deve conter os nomes das
+// colunas, de forma que a ordem é livre e colunas desconhecidas são
+// ignoradas, permitindo importar o resultado da exportação
+type csvReader struct {
+	cr      *csv.Reader
+	columns map[string]int
+	userID  uuid.UUID
+}
+
+func newCSVReader(r io.Reader, userID uuid.UUID) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, validate.NewFieldsError("header", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	if _, exists := columns["name"]; !exists {
+		return nil, validate.NewFieldsError("header", errors.New("column name is required"))
+	}
+
+	return &csvReader{
+		cr:      cr,
+		columns: columns,
+		userID:  userID,
+	}, nil
+}
+
+// Next implementa product.RowReader
+func (cr *csvReader) Next() (product.NewProduct, error) {
+	record, err := cr.cr.Read()
+	if err != nil {
+		var pe *csv.ParseError
+		if errors.As(err, &pe) {
+			return product.NewProduct{}, validate.NewFieldsError("", pe.Err)
+		}
+		return product.NewProduct{}, err
+	}
+
+	app := AppImportProduct{
+		Name:       cr.value(record, "name"),
+		CategoryID: cr.value(record, "category_id"),
+	}
+
+	var fieldErrs validate.FieldErrors
+
+	currency := strings.ToUpper(cr.value(record, "currency"))
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+
+	if cost := cr.value(record, "cost"); cost != "" {
+		if app.Cost, err = money.Parse(cost, currency); err != nil {
+			fieldErrs = append(fieldErrs, validate.FieldError{Field: "cost", Err: err.Error()})
+		}
+	}
+
+	if quantity := cr.value(record, "quantity"); quantity != "" {
+		if app.Quantity, err = strconv.Atoi(quantity); err != nil {
+			fieldErrs = append(fieldErrs, validate.FieldError{Field: "quantity", Err: err.Error()})
+		}
+	}
+
+	if tags := cr.value(record, "tags"); tags != "" {
+		app.Tags = strings.Split(tags, ",")
+	}
+
+	if len(fieldErrs) > 0 {
+		return product.NewProduct{}, fieldErrs
+	}
+
+	return toCoreNewProduct(cr.userID, app)
+}
+
+// value retorna o valor da coluna na linha, ou vazio se a coluna não existir
+func (cr *csvReader) value(record []string, column string) string {
+	i, exists := cr.columns[column]
+	if !exists || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// =============================================================================
+
+// ndjsonReader lê produtos de um NDJSON, um objeto JSON por linha. Linhas em
+// branco são ignoradas
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	userID  uuid.UUID
+}
+
+func newNDJSONReader(r io.Reader, userID uuid.UUID) *ndjsonReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLine)
+
+	return &ndjsonReader{
+		scanner: scanner,
+		userID:  userID,
+	}
+}
+
+// Next implementa product.RowReader
+func (nr *ndjsonReader) Next() (product.NewProduct, error) {
+	for nr.scanner.Scan() {
+		line := bytes.TrimSpace(nr.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var app AppImportProduct
+		if err := json.Unmarshal(line, &app); err != nil {
+			return product.NewProduct{}, validate.NewFieldsError("", err)
+		}
+
+		return toCoreNewProduct(nr.userID, app)
+	}
+
+	if err := nr.scanner.Err(); err != nil {
+		return product.NewProduct{}, err
+	}
+
+	return product.NewProduct{}, io.EOF
+}
@@ -0,0 +1,130 @@
+package productgrp
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/core/product"
+	"github.com/vitoraalmeida/service/business/data/money"
+	"github.com/vitoraalmeida/service/business/sys/validate"
+)
+
+// AppProduct representa informação referente a um produto no contexto de aplicação
+type AppProduct struct {
+	ID          string      `json:"id"`
+	UserID      string      `json:"userID"`
+	Name        string      `json:"name"`
+	Cost        money.Money `json:"cost"`
+	Quantity    int         `json:"quantity"`
+	Sold        int         `json:"sold"`
+	Revenue     money.Money `json:"revenue"`
+	CategoryID  string      `json:"categoryID,omitempty"`
+	Tags        []string    `json:"tags"`
+	DateCreated string      `json:"dateCreated"`
+	DateUpdated string      `json:"dateUpdated"`
+}
+
+// Converte um produto de domínio em produto de aplicação
+func toAppProduct(prd product.Product) AppProduct {
+	var categoryID string
+	if prd.CategoryID != nil {
+		categoryID = prd.CategoryID.String()
+	}
+
+	tags := prd.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return AppProduct{
+		ID:          prd.ID.String(),
+		UserID:      prd.UserID.String(),
+		Name:        prd.Name,
+		Cost:        prd.Cost,
+		Quantity:    prd.Quantity,
+		Sold:        prd.Sold,
+		Revenue:     prd.Revenue,
+		CategoryID:  categoryID,
+		Tags:        tags,
+		DateCreated: prd.DateCreated.Format(time.RFC3339),
+		DateUpdated: prd.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+// =============================================================================
+
+// AppImportProduct contém os dados de uma linha de importação de produtos
+type AppImportProduct struct {
+	Name       string      `json:"name" validate:"required"`
+	Cost       money.Money `json:"cost"`
+	Quantity   int         `json:"quantity" validate:"gte=0"`
+	CategoryID string      `json:"categoryID" validate:"omitempty,uuid4"`
+	Tags       []string    `json:"tags"`
+}
+
+// Validate checa se a linha está no formato correto
+func (app AppImportProduct) Validate() error {
+	err := validate.Check(app)
+
+	fieldErrs := validate.GetFieldErrors(err)
+	if err != nil && fieldErrs == nil {
+		return err
+	}
+
+	if app.Cost.IsNegative() {
+		fieldErrs = append(fieldErrs, validate.FieldError{Field: "cost", Err: "cost must be 0 or greater"})
+	}
+
+	if len(fieldErrs) > 0 {
+		return fieldErrs
+	}
+
+	return nil
+}
+
+// Converte uma linha de importação em produto de domínio. O produto pertence
+// ao usuário que fez a importação
+func toCoreNewProduct(userID uuid.UUID, app AppImportProduct) (product.NewProduct, error) {
+	if err := app.Validate(); err != nil {
+		return product.NewProduct{}, err
+	}
+
+	np := product.NewProduct{
+		Name:     app.Name,
+		Cost:     app.Cost,
+		Quantity: app.Quantity,
+		UserID:   userID,
+		Tags:     app.Tags,
+	}
+
+	if app.CategoryID != "" {
+		id, err := uuid.Parse(app.CategoryID)
+		if err != nil {
+			return product.NewProduct{}, validate.NewFieldsError("categoryID", err)
+		}
+		np.CategoryID = &id
+	}
+
+	return np, nil
+}
+
+// AppImportReport representa o resultado de uma importação
+type AppImportReport struct {
+	Imported int                  `json:"imported"`
+	Failed   int                  `json:"failed"`
+	Errors   validate.FieldErrors `json:"errors"`
+}
+
+// Converte o relatório de domínio em relatório de aplicação
+func toAppImportReport(report product.ImportReport) AppImportReport {
+	errs := report.Errors
+	if errs == nil {
+		errs = validate.FieldErrors{}
+	}
+
+	return AppImportReport{
+		Imported: report.Imported,
+		Failed:   report.Failed,
+		Errors:   errs,
+	}
+}
@@ -0,0 +1,23 @@
+package productgrp
+
+import (
+	"net/http"
+
+	"github.com/vitoraalmeida/service/business/core/product"
+	"github.com/vitoraalmeida/service/business/data/order"
+)
+
+// conjunto de todos os campos possíveis pelos quais podemos ordenar os resultados
+var orderByFields = map[string]struct{}{
+	product.OrderByProdID:   {},
+	product.OrderByName:     {},
+	product.OrderByCost:     {},
+	product.OrderByQuantity: {},
+	product.OrderBySold:     {},
+	product.OrderByRevenue:  {},
+	product.OrderByUserID:   {},
+}
+
+func parseOrder(r *http.Request) (order.By, error) {
+	return order.Parse(r, product.DefaultOrderBy, orderByFields)
+}
@@ -0,0 +1,293 @@
+// Package productgrp mantém o grupo de handlers para acesso a produtos.
+package productgrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/core/exchange"
+	"github.com/vitoraalmeida/service/business/core/product"
+	"github.com/vitoraalmeida/service/business/data/order"
+	"github.com/vitoraalmeida/service/business/sys/validate"
+	"github.com/vitoraalmeida/service/business/web/auth"
+	v1 "github.com/vitoraalmeida/service/business/web/v1"
+	"github.com/vitoraalmeida/service/business/web/v1/paging"
+	"github.com/vitoraalmeida/service/foundation/web"
+)
+
+// Handlers gerencia o conjunto de endpoints de produtos
+type Handlers struct {
+	product  *product.Core
+	exchange *exchange.Core // converte os valores quando o parâmetro currency é informado
+	paging   paging.Config  // limites de paginação das rotas de consulta
+}
+
+// New constrói os handlers para acesso às rotas
+func New(product *product.Core, exchange *exchange.Core, pagingCfg paging.Config) *Handlers {
+	return &Handlers{
+		product:  product,
+		exchange: exchange,
+		paging:   pagingCfg,
+	}
+}
+
+// Query retorna uma lista de produtos paginada. Se o parâmetro cursor for
+// informado, usa paginação por cursor ao invés de número de página. Se o
+// parâmetro currency for informado, os valores são convertidos para a moeda
+func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	filter, err := parseFilter(r)
+	if err != nil {
+		return err
+	}
+
+	currency, err := parseCurrency(r)
+	if err != nil {
+		return err
+	}
+
+	orderBy, err := parseOrder(r)
+	if err != nil {
+		return err
+	}
+
+	if paging.IsCursorRequest(r) {
+		return h.queryByCursor(ctx, w, r, filter, orderBy, currency)
+	}
+
+	page, err := paging.ParseRequest(r, h.paging)
+	if err != nil {
+		return err
+	}
+
+	prds, err := h.product.Query(ctx, filter, orderBy, page.Number, page.RowsPerPage)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	if prds, err = h.convert(ctx, prds, currency); err != nil {
+		return err
+	}
+
+	items := make([]AppProduct, len(prds))
+	for i, prd := range prds {
+		items[i] = toAppProduct(prd)
+	}
+
+	total, err := h.product.Count(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("count: %w", err)
+	}
+
+	return web.Respond(ctx, w, paging.NewResponse(items, total, page.Number, page.RowsPerPage), http.StatusOK)
+}
+
+// queryByCursor retorna uma página de produtos a partir de um cursor
+func (h *Handlers) queryByCursor(ctx context.Context, w http.ResponseWriter, r *http.Request, filter product.QueryFilter, orderBy order.By, currency string) error {
+	page, err := paging.ParseCursorRequest(r, orderBy, h.paging)
+	if err != nil {
+		return err
+	}
+
+	prds, cursorPage, err := h.product.QueryByCursor(ctx, filter, orderBy, page.Cursor, page.RowsPerPage)
+	if err != nil {
+		return fmt.Errorf("querybycursor: %w", err)
+	}
+
+	if prds, err = h.convert(ctx, prds, currency); err != nil {
+		return err
+	}
+
+	items := make([]AppProduct, len(prds))
+	for i, prd := range prds {
+		items[i] = toAppProduct(prd)
+	}
+
+	return web.Respond(ctx, w, paging.NewCursorResponse(items, cursorPage, page.RowsPerPage), http.StatusOK)
+}
+
+// QueryByID retorna um produto pelo seu ID. A resposta carrega ETag e
+// Last-Modified para que clientes possam fazer requisições condicionais
+func (h *Handlers) QueryByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id, err := uuid.Parse(web.Param(r, "product_id"))
+	if err != nil {
+		return validate.NewFieldsError("product_id", err)
+	}
+
+	currency, err := parseCurrency(r)
+	if err != nil {
+		return err
+	}
+
+	prd, err := h.product.QueryByID(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, product.ErrNotFound):
+			return v1.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querybyid: id[%s]: %w", id, err)
+		}
+	}
+
+	lastModified, err := h.lastModified(ctx, prd, currency)
+	if err != nil {
+		return err
+	}
+
+	prds, err := h.convert(ctx, []product.Product{prd}, currency)
+	if err != nil {
+		return err
+	}
+	prd = prds[0]
+
+	return web.RespondConditional(ctx, w, r, toAppProduct(prd), lastModified, http.StatusOK)
+}
+
+// Import cadastra em nome do usuário autenticado os produtos enviados no
+// corpo da requisição, em CSV ou NDJSON. O corpo é lido aos poucos, então
+// arquivos grandes não são carregados inteiros em memória. O parâmetro mode
+// define se uma linha inválida desfaz a importação (atomic) ou é apenas
+// ignorada (tolerant). Em ambos os casos a resposta traz os erros por linha
+func (h *Handlers) Import(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	userID, err := auth.GetUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	mode, err := parseImportMode(r)
+	if err != nil {
+		return err
+	}
+
+	rr, err := newRowReader(r, userID)
+	if err != nil {
+		return err
+	}
+
+	report, err := h.product.Import(ctx, rr, mode)
+	if err != nil {
+		switch {
+		case errors.Is(err, product.ErrImportFailed):
+			return web.Respond(ctx, w, toAppImportReport(report), http.StatusBadRequest)
+		case errors.Is(err, product.ErrUnknownUser):
+			return v1.NewRequestError(err, http.StatusForbidden)
+		default:
+			return fmt.Errorf("import: %w", err)
+		}
+	}
+
+	return web.Respond(ctx, w, toAppImportReport(report), http.StatusOK)
+}
+
+// Export envia os produtos que atendem aos mesmos filtros e ordenação da
+// consulta, em CSV ou NDJSON conforme o parâmetro format. As linhas são
+// enviadas conforme são lidas do banco, sem paginação. Uma falha no meio da
+// exportação é informada no trailer Stream-Error
+func (h *Handlers) Export(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	filter, err := parseFilter(r)
+	if err != nil {
+		return err
+	}
+
+	orderBy, err := parseOrder(r)
+	if err != nil {
+		return err
+	}
+
+	format, err := parseExportFormat(r)
+	if err != nil {
+		return err
+	}
+
+	f := func(w io.Writer, flush func()) error {
+		ew := format.newWriter(w)
+
+		var rows int
+		write := func(prd product.Product) error {
+			if err := ew.Write(toAppProduct(prd)); err != nil {
+				return err
+			}
+
+			if rows++; rows%exportFlushRows == 0 {
+				if err := ew.Flush(); err != nil {
+					return err
+				}
+				flush()
+			}
+
+			return nil
+		}
+
+		if err := h.product.QueryEach(ctx, filter, orderBy, write); err != nil {
+			return fmt.Errorf("export: %w", err)
+		}
+
+		if err := ew.Flush(); err != nil {
+			return err
+		}
+		flush()
+
+		return nil
+	}
+
+	return web.Stream(ctx, w, format.contentType, format.filename, f)
+}
+
+// lastModified retorna a data da última alteração da representação do
+// produto. Com conversão de moeda a resposta também muda quando as taxas da
+// moeda do produto ou da moeda pedida são atualizadas, então a data mais
+// recente entre elas é usada
+func (h *Handlers) lastModified(ctx context.Context, prd product.Product, currency string) (time.Time, error) {
+	lastModified := prd.DateUpdated
+	if currency == "" || currency == prd.Cost.Currency() {
+		return lastModified, nil
+	}
+
+	for _, cur := range []string{prd.Cost.Currency(), currency} {
+		rate, err := h.exchange.QueryByCurrency(ctx, cur)
+		if err != nil {
+			if errors.Is(err, exchange.ErrNotFound) {
+				return time.Time{}, validate.NewFieldsError("currency", exchange.ErrNotFound)
+			}
+			return time.Time{}, fmt.Errorf("lastmodified: %w", err)
+		}
+
+		if rate.DateUpdated.After(lastModified) {
+			lastModified = rate.DateUpdated
+		}
+	}
+
+	return lastModified, nil
+}
+
+// convert converte custo e receita dos produtos para a moeda informada. Sem
+// moeda, os produtos são mantidos na moeda em que foram cadastrados
+func (h *Handlers) convert(ctx context.Context, prds []product.Product, currency string) ([]product.Product, error) {
+	if currency == "" {
+		return prds, nil
+	}
+
+	rates, err := h.exchange.Rates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("rates: %w", err)
+	}
+
+	if _, exists := rates[currency]; !exists {
+		return nil, validate.NewFieldsError("currency", exchange.ErrNotFound)
+	}
+
+	for i, prd := range prds {
+		if prds[i].Cost, err = rates.Convert(prd.Cost, currency); err != nil {
+			return nil, fmt.Errorf("convert: productID[%s]: %w", prd.ID, err)
+		}
+		if prds[i].Revenue, err = rates.Convert(prd.Revenue, currency); err != nil {
+			return nil, fmt.Errorf("convert: productID[%s]: %w", prd.ID, err)
+		}
+	}
+
+	return prds, nil
+}
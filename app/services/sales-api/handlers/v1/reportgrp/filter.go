@@ -0,0 +1,87 @@
+package reportgrp
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vitoraalmeida/service/business/cview/report"
+	"github.com/vitoraalmeida/service/business/sys/validate"
+)
+
+// período usado quando o cliente não informa o início
+const defaultPeriod = 30 * 24 * time.Hour
+
+// limites para a quantidade de usuários do ranking
+const (
+	defaultLimit = 10
+	maxLimit     = 100
+)
+
+// parseFilter lê o período dos parâmetros start_created_date e
+// end_created_date. Sem fim, o período termina agora; sem início, começa 30
+// dias antes do fim
+func parseFilter(r *http.Request) (report.QueryFilter, error) {
+	values := r.URL.Query()
+
+	end := time.Now()
+	if createdDate := values.Get("end_created_date"); createdDate != "" {
+		t, err := time.Parse(time.RFC3339, createdDate)
+		if err != nil {
+			return report.QueryFilter{}, validate.NewFieldsError("end_created_date", err)
+		}
+		end = t
+	}
+
+	start := end.Add(-defaultPeriod)
+	if createdDate := values.Get("start_created_date"); createdDate != "" {
+		t, err := time.Parse(time.RFC3339, createdDate)
+		if err != nil {
+			return report.QueryFilter{}, validate.NewFieldsError("start_created_date", err)
+		}
+		start = t
+	}
+
+	filter := report.NewQueryFilter(start, end)
+	if err := filter.Validate(); err != nil {
+		return report.QueryFilter{}, err
+	}
+
+	return filter, nil
+}
+
+// parseBucket lê o tamanho dos intervalos do parâmetro bucket. Por padrão os
+// dados são agrupados por dia
+func parseBucket(r *http.Request) (report.Bucket, error) {
+	value := r.URL.Query().Get("bucket")
+	if value == "" {
+		return report.BucketDay, nil
+	}
+
+	bucket, err := report.ParseBucket(value)
+	if err != nil {
+		return report.Bucket{}, validate.NewFieldsError("bucket", fmt.Errorf("bucket %q must be day, week or month", value))
+	}
+
+	return bucket, nil
+}
+
+// parseLimit lê a quantidade de usuários do ranking do parâmetro limit
+func parseLimit(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return defaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, validate.NewFieldsError("limit", err)
+	}
+
+	if limit < 1 || limit > maxLimit {
+		return 0, validate.NewFieldsError("limit", fmt.Errorf("limit must be between 1 and %d", maxLimit))
+	}
+
+	return limit, nil
+}
@@ -0,0 +1,88 @@
+package reportgrp
+
+import (
+	"time"
+
+	"github.com/vitoraalmeida/service/business/cview/report"
+	"github.com/vitoraalmeida/service/business/data/money"
+)
+
+// AppPeriod descreve o período e o intervalo usados num relatório
+type AppPeriod struct {
+	StartCreatedDate string `json:"startCreatedDate"`
+	EndCreatedDate   string `json:"endCreatedDate"`
+	Bucket           string `json:"bucket,omitempty"`
+}
+
+func toAppPeriod(filter report.QueryFilter, bucket report.Bucket) AppPeriod {
+	return AppPeriod{
+		StartCreatedDate: filter.StartCreatedDate.Format(time.RFC3339),
+		EndCreatedDate:   filter.EndCreatedDate.Format(time.RFC3339),
+		Bucket:           bucket.Name(),
+	}
+}
+
+// AppReport é a resposta dos relatórios, com o período considerado
+type AppReport[T any] struct {
+	Period AppPeriod `json:"period"`
+	Items  []T       `json:"items"`
+}
+
+// =============================================================================
+
+// AppProductsCreated representa os produtos cadastrados num intervalo
+type AppProductsCreated struct {
+	Period     string      `json:"period"`
+	TotalCount int         `json:"totalCount"`
+	TotalCost  money.Money `json:"totalCost"`
+}
+
+func toAppProductsCreated(prc report.ProductsCreated) AppProductsCreated {
+	return AppProductsCreated{
+		Period:     prc.Period.Format(time.RFC3339),
+		TotalCount: prc.TotalCount,
+		TotalCost:  prc.TotalCost,
+	}
+}
+
+// AppDepartmentCost representa a distribuição de custo de um departamento
+type AppDepartmentCost struct {
+	Department string      `json:"department"`
+	TotalUsers int         `json:"totalUsers"`
+	TotalCount int         `json:"totalCount"`
+	TotalCost  money.Money `json:"totalCost"`
+	MinCost    money.Money `json:"minCost"`
+	AvgCost    money.Money `json:"avgCost"`
+	MaxCost    money.Money `json:"maxCost"`
+}
+
+func toAppDepartmentCost(dpc report.DepartmentCost) AppDepartmentCost {
+	return AppDepartmentCost{
+		Department: dpc.Department,
+		TotalUsers: dpc.TotalUsers,
+		TotalCount: dpc.TotalCount,
+		TotalCost:  dpc.TotalCost,
+		MinCost:    dpc.MinCost,
+		AvgCost:    dpc.AvgCost,
+		MaxCost:    dpc.MaxCost,
+	}
+}
+
+// AppUserInventory representa o valor em estoque de um usuário
+type AppUserInventory struct {
+	UserID         string      `json:"userID"`
+	UserName       string      `json:"userName"`
+	TotalCount     int         `json:"totalCount"`
+	TotalQuantity  int         `json:"totalQuantity"`
+	InventoryValue money.Money `json:"inventoryValue"`
+}
+
+func toAppUserInventory(usi report.UserInventory) AppUserInventory {
+	return AppUserInventory{
+		UserID:         usi.UserID.String(),
+		UserName:       usi.UserName,
+		TotalCount:     usi.TotalCount,
+		TotalQuantity:  usi.TotalQuantity,
+		InventoryValue: usi.InventoryValue,
+	}
+}
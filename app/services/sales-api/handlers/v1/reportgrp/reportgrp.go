@@ -0,0 +1,96 @@
+// Package reportgrp mantém o grupo de handlers dos relatórios gerenciais.
+package reportgrp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/vitoraalmeida/service/business/cview/report"
+	"github.com/vitoraalmeida/service/foundation/web"
+)
+
+// Handlers gerencia o conjunto de endpoints de relatórios
+type Handlers struct {
+	report *report.Core
+}
+
+// New constrói os handlers para acesso às rotas
+func New(report *report.Core) *Handlers {
+	return &Handlers{
+		report: report,
+	}
+}
+
+// ProductsCreated retorna a quantidade e o custo dos produtos cadastrados em
+// cada intervalo do período, de acordo com o parâmetro bucket
+func (h *Handlers) ProductsCreated(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	filter, err := parseFilter(r)
+	if err != nil {
+		return err
+	}
+
+	bucket, err := parseBucket(r)
+	if err != nil {
+		return err
+	}
+
+	prcs, err := h.report.QueryProductsCreated(ctx, filter, bucket)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	items := make([]AppProductsCreated, len(prcs))
+	for i, prc := range prcs {
+		items[i] = toAppProductsCreated(prc)
+	}
+
+	return web.Respond(ctx, w, AppReport[AppProductsCreated]{Period: toAppPeriod(filter, bucket), Items: items}, http.StatusOK)
+}
+
+// DepartmentCost retorna a distribuição do custo dos produtos cadastrados no
+// período por departamento
+func (h *Handlers) DepartmentCost(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	filter, err := parseFilter(r)
+	if err != nil {
+		return err
+	}
+
+	dpcs, err := h.report.QueryDepartmentCost(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	items := make([]AppDepartmentCost, len(dpcs))
+	for i, dpc := range dpcs {
+		items[i] = toAppDepartmentCost(dpc)
+	}
+
+	return web.Respond(ctx, w, AppReport[AppDepartmentCost]{Period: toAppPeriod(filter, report.Bucket{}), Items: items}, http.StatusOK)
+}
+
+// TopUsersByInventory retorna os usuários com maior valor em estoque entre os
+// produtos cadastrados no período. O parâmetro limit define quantos
+func (h *Handlers) TopUsersByInventory(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	filter, err := parseFilter(r)
+	if err != nil {
+		return err
+	}
+
+	limit, err := parseLimit(r)
+	if err != nil {
+		return err
+	}
+
+	usis, err := h.report.QueryTopUsersByInventory(ctx, filter, limit)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	items := make([]AppUserInventory, len(usis))
+	for i, usi := range usis {
+		items[i] = toAppUserInventory(usi)
+	}
+
+	return web.Respond(ctx, w, AppReport[AppUserInventory]{Period: toAppPeriod(filter, report.Bucket{}), Items: items}, http.StatusOK)
+}
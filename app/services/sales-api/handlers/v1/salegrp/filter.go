@@ -0,0 +1,71 @@
+package salegrp
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/core/sale"
+	"github.com/vitoraalmeida/service/business/data/expr"
+	"github.com/vitoraalmeida/service/business/sys/validate"
+)
+
+// Verifica se a Query string contém campos que indicam filtros de resultados
+func parseFilter(r *http.Request) (sale.QueryFilter, error) {
+	values := r.URL.Query()
+
+	var filter sale.QueryFilter
+
+	if saleID := values.Get("sale_id"); saleID != "" {
+		id, err := uuid.Parse(saleID)
+		if err != nil {
+			return sale.QueryFilter{}, validate.NewFieldsError("sale_id", err)
+		}
+		filter.WithSaleID(id)
+	}
+
+	if userID := values.Get("user_id"); userID != "" {
+		id, err := uuid.Parse(userID)
+		if err != nil {
+			return sale.QueryFilter{}, validate.NewFieldsError("user_id", err)
+		}
+		filter.WithUserID(id)
+	}
+
+	if productID := values.Get("product_id"); productID != "" {
+		id, err := uuid.Parse(productID)
+		if err != nil {
+			return sale.QueryFilter{}, validate.NewFieldsError("product_id", err)
+		}
+		filter.WithProductID(id)
+	}
+
+	if createdDate := values.Get("start_created_date"); createdDate != "" {
+		t, err := time.Parse(time.RFC3339, createdDate)
+		if err != nil {
+			return sale.QueryFilter{}, validate.NewFieldsError("start_created_date", err)
+		}
+		filter.WithStartDateCreated(t)
+	}
+
+	if createdDate := values.Get("end_created_date"); createdDate != "" {
+		t, err := time.Parse(time.RFC3339, createdDate)
+		if err != nil {
+			return sale.QueryFilter{}, validate.NewFieldsError("end_created_date", err)
+		}
+		filter.WithEndCreatedDate(t)
+	}
+
+	// condições livres no formato filter=campo<op>valor,...
+	exp, err := expr.Parse(r, sale.FilterFields)
+	if err != nil {
+		return sale.QueryFilter{}, err
+	}
+	filter.WithExpression(exp)
+
+	if err := filter.Validate(); err != nil {
+		return sale.QueryFilter{}, err
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,99 @@
+package salegrp
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/core/sale"
+	"github.com/vitoraalmeida/service/business/data/money"
+	"github.com/vitoraalmeida/service/business/sys/validate"
+)
+
+// AppSale representa informação referente a uma venda no contexto de aplicação
+type AppSale struct {
+	ID          string        `json:"id"`
+	UserID      string        `json:"userID"`
+	Items       []AppSaleItem `json:"items"`
+	Total       money.Money   `json:"total"`
+	DateCreated string        `json:"dateCreated"`
+}
+
+// AppSaleItem representa um produto vendido numa venda
+type AppSaleItem struct {
+	ProductID string      `json:"productID"`
+	Quantity  int         `json:"quantity"`
+	Price     money.Money `json:"price"`
+}
+
+// Converte uma venda de domínio em venda de aplicação
+func toAppSale(sl sale.Sale) AppSale {
+	items := make([]AppSaleItem, len(sl.Items))
+	for i, itm := range sl.Items {
+		items[i] = AppSaleItem{
+			ProductID: itm.ProductID.String(),
+			Quantity:  itm.Quantity,
+			Price:     itm.Price,
+		}
+	}
+
+	return AppSale{
+		ID:          sl.ID.String(),
+		UserID:      sl.UserID.String(),
+		Items:       items,
+		Total:       sl.Total,
+		DateCreated: sl.DateCreated.Format(time.RFC3339),
+	}
+}
+
+// =============================================================================
+
+// AppNewSale contém os dados para registrar uma venda
+type AppNewSale struct {
+	Items []AppNewSaleItem `json:"items" validate:"required,min=1,dive"`
+}
+
+// AppNewSaleItem contém o produto e a quantidade vendida
+type AppNewSaleItem struct {
+	ProductID string `json:"productID" validate:"required,uuid4"`
+	Quantity  int    `json:"quantity" validate:"required,gte=1"`
+}
+
+// Validate checa se os dados estão no formato correto. Um mesmo produto não
+// pode aparecer mais de uma vez na venda
+func (app AppNewSale) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return err
+	}
+
+	seen := make(map[string]struct{}, len(app.Items))
+	for _, itm := range app.Items {
+		if _, exists := seen[itm.ProductID]; exists {
+			return validate.NewFieldsError("items", fmt.Errorf("product %s appears more than once", itm.ProductID))
+		}
+		seen[itm.ProductID] = struct{}{}
+	}
+
+	return nil
+}
+
+// Converte os dados de aplicação no modelo de domínio para registrar a venda
+func toCoreNewSale(userID uuid.UUID, app AppNewSale) (sale.NewSale, error) {
+	items := make([]sale.NewItem, len(app.Items))
+	for i, itm := range app.Items {
+		productID, err := uuid.Parse(itm.ProductID)
+		if err != nil {
+			return sale.NewSale{}, fmt.Errorf("parsing productID: %w", err)
+		}
+
+		items[i] = sale.NewItem{
+			ProductID: productID,
+			Quantity:  itm.Quantity,
+		}
+	}
+
+	return sale.NewSale{
+		UserID: userID,
+		Items:  items,
+	}, nil
+}
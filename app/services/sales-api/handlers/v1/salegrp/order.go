@@ -0,0 +1,20 @@
+package salegrp
+
+import (
+	"net/http"
+
+	"github.com/vitoraalmeida/service/business/core/sale"
+	"github.com/vitoraalmeida/service/business/data/order"
+)
+
+// conjunto de todos os campos possíveis pelos quais podemos ordenar os resultados
+var orderByFields = map[string]struct{}{
+	sale.OrderBySaleID:      {},
+	sale.OrderByUserID:      {},
+	sale.OrderByTotal:       {},
+	sale.OrderByDateCreated: {},
+}
+
+func parseOrder(r *http.Request) (order.By, error) {
+	return order.Parse(r, sale.DefaultOrderBy, orderByFields)
+}
@@ -0,0 +1,164 @@
+// Package salegrp mantém o grupo de handlers para registro e consulta de vendas.
+package salegrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/core/product"
+	"github.com/vitoraalmeida/service/business/core/sale"
+	"github.com/vitoraalmeida/service/business/sys/validate"
+	"github.com/vitoraalmeida/service/business/web/auth"
+	v1 "github.com/vitoraalmeida/service/business/web/v1"
+	"github.com/vitoraalmeida/service/business/web/v1/paging"
+	"github.com/vitoraalmeida/service/foundation/web"
+)
+
+// Handlers gerencia o conjunto de endpoints de vendas
+type Handlers struct {
+	sale   *sale.Core
+	auth   *auth.Auth
+	paging paging.Config
+}
+
+// New constrói os handlers para acesso às rotas
+func New(sale *sale.Core, auth *auth.Auth, pagingCfg paging.Config) *Handlers {
+	return &Handlers{
+		sale:   sale,
+		auth:   auth,
+		paging: pagingCfg,
+	}
+}
+
+// Create registra uma venda em nome do usuário autenticado, dando baixa no
+// estoque dos produtos vendidos
+func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var app AppNewSale
+	if err := web.Decode(r, &app); err != nil {
+		if validate.IsFieldErrors(err) {
+			return err
+		}
+		return v1.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	userID, err := auth.GetUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	ns, err := toCoreNewSale(userID, app)
+	if err != nil {
+		return v1.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	sl, err := h.sale.Create(ctx, ns)
+	if err != nil {
+		switch {
+		case errors.Is(err, sale.ErrInsufficientStock):
+			return v1.NewRequestError(err, http.StatusConflict)
+		case errors.Is(err, product.ErrNotFound):
+			return v1.NewRequestError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("create: sale[%+v]: %w", ns, err)
+		}
+	}
+
+	return web.Respond(ctx, w, toAppSale(sl), http.StatusCreated)
+}
+
+// Query retorna uma lista de vendas paginada. Administradores veem as vendas
+// de todos os usuários, os demais apenas as próprias
+func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	page, err := paging.ParseRequest(r, h.paging)
+	if err != nil {
+		return err
+	}
+
+	filter, err := parseFilter(r)
+	if err != nil {
+		return err
+	}
+
+	orderBy, err := parseOrder(r)
+	if err != nil {
+		return err
+	}
+
+	if err := h.scope(ctx, &filter); err != nil {
+		return err
+	}
+
+	sls, err := h.sale.Query(ctx, filter, orderBy, page.Number, page.RowsPerPage)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	items := make([]AppSale, len(sls))
+	for i, sl := range sls {
+		items[i] = toAppSale(sl)
+	}
+
+	total, err := h.sale.Count(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("count: %w", err)
+	}
+
+	return web.Respond(ctx, w, paging.NewResponse(items, total, page.Number, page.RowsPerPage), http.StatusOK)
+}
+
+// QueryByID retorna uma venda pelo seu ID. A venda de outro usuário é
+// respondida como não encontrada para quem não é administrador, para não
+// revelar quais IDs existem
+func (h *Handlers) QueryByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id, err := uuid.Parse(web.Param(r, "sale_id"))
+	if err != nil {
+		return validate.NewFieldsError("sale_id", err)
+	}
+
+	sl, err := h.sale.QueryByID(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sale.ErrNotFound):
+			return v1.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querybyid: id[%s]: %w", id, err)
+		}
+	}
+
+	var filter sale.QueryFilter
+	if err := h.scope(ctx, &filter); err != nil {
+		return err
+	}
+
+	if filter.UserID != nil && *filter.UserID != sl.UserID {
+		return v1.NewRequestError(sale.ErrNotFound, http.StatusNotFound)
+	}
+
+	return web.Respond(ctx, w, toAppSale(sl), http.StatusOK)
+}
+
+// scope restringe as vendas às do usuário autenticado quando ele não é
+// administrador
+func (h *Handlers) scope(ctx context.Context, filter *sale.QueryFilter) error {
+	claims := auth.GetClaims(ctx)
+
+	err := h.auth.Authorize(ctx, claims, auth.RuleAdminOnly)
+	switch {
+	case err == nil:
+		return nil
+	case !errors.Is(err, auth.ErrForbidden):
+		return fmt.Errorf("scope: %w", err)
+	}
+
+	userID, err := auth.GetUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	filter.WithUserID(userID)
+
+	return nil
+}
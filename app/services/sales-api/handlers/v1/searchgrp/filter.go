@@ -0,0 +1,22 @@
+package searchgrp
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/vitoraalmeida/service/business/cview/search"
+)
+
+// Verifica se a Query string contém o texto buscado e o tipo de registro
+func parseFilter(r *http.Request) search.QueryFilter {
+	values := r.URL.Query()
+
+	var filter search.QueryFilter
+	filter.WithText(strings.TrimSpace(values.Get("q")))
+
+	if kind := values.Get("kind"); kind != "" {
+		filter.WithKind(kind)
+	}
+
+	return filter
+}
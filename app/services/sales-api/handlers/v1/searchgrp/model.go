@@ -0,0 +1,25 @@
+package searchgrp
+
+import (
+	"github.com/vitoraalmeida/service/business/cview/search"
+)
+
+// AppResult representa um registro encontrado na busca no contexto de aplicação
+type AppResult struct {
+	Kind      string  `json:"kind"`
+	ID        string  `json:"id"`
+	Title     string  `json:"title"`
+	Highlight string  `json:"highlight"`
+	Rank      float64 `json:"rank"`
+}
+
+// Converte um resultado de domínio em resultado de aplicação
+func toAppResult(res search.Result) AppResult {
+	return AppResult{
+		Kind:      res.Kind,
+		ID:        res.ID.String(),
+		Title:     res.Title,
+		Highlight: res.Highlight,
+		Rank:      res.Rank,
+	}
+}
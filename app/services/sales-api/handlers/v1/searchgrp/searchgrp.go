@@ -0,0 +1,93 @@
+// Package searchgrp mantém o grupo de handlers para busca textual.
+package searchgrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/cview/search"
+	"github.com/vitoraalmeida/service/business/web/auth"
+	"github.com/vitoraalmeida/service/business/web/v1/paging"
+	"github.com/vitoraalmeida/service/foundation/web"
+)
+
+// Handlers gerencia o conjunto de endpoints de busca
+type Handlers struct {
+	search *search.Core
+	auth   *auth.Auth
+	paging paging.Config
+}
+
+// New constrói os handlers para acesso às rotas
+func New(search *search.Core, auth *auth.Auth, pagingCfg paging.Config) *Handlers {
+	return &Handlers{
+		search: search,
+		auth:   auth,
+		paging: pagingCfg,
+	}
+}
+
+// Query busca usuários e produtos pelo texto informado em q, retornando os
+// resultados por relevância. Administradores encontram qualquer usuário,
+// os demais encontram apenas a si mesmos
+func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	page, err := paging.ParseRequest(r, h.paging)
+	if err != nil {
+		return err
+	}
+
+	filter := parseFilter(r)
+	if err := h.scopeUsers(ctx, &filter); err != nil {
+		return err
+	}
+
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+
+	ress, err := h.search.Query(ctx, filter, page.Number, page.RowsPerPage)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	items := make([]AppResult, len(ress))
+	for i, res := range ress {
+		items[i] = toAppResult(res)
+	}
+
+	total, err := h.search.Count(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("count: %w", err)
+	}
+
+	return web.Respond(ctx, w, paging.NewResponse(items, total, page.Number, page.RowsPerPage), http.StatusOK)
+}
+
+// scopeUsers restringe os usuários que o chamador pode encontrar, usando a
+// mesma política de autorização das demais rotas. Uma falha ao avaliar a
+// política é retornada, em vez de tratar o chamador como não administrador
+func (h *Handlers) scopeUsers(ctx context.Context, filter *search.QueryFilter) error {
+	claims := auth.GetClaims(ctx)
+
+	err := h.auth.Authorize(ctx, claims, auth.RuleAdminOnly)
+	switch {
+	case err == nil:
+		return nil
+	case !errors.Is(err, auth.ErrForbidden):
+		return fmt.Errorf("scopeusers: %w", err)
+	}
+
+	// tokens cujo subject não é um usuário do banco não enxergam usuários
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		filter.WithoutUsers()
+		return nil
+	}
+
+	filter.WithUserScope(userID)
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package usergrp
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// a cada exportFlushRows linhas o conteúdo é enviado ao cliente
+const exportFlushRows = 100
+
+// colunas do CSV do relatório de resumo
+var summaryCSVColumns = []string{"user_id", "user_name", "total_count", "total_cost", "currency"}
+
+// summaryCSVWriter escreve o relatório de resumo em CSV, com o cabeçalho na
+// primeira linha
+type summaryCSVWriter struct {
+	cw     *csv.Writer
+	header bool
+}
+
+func newSummaryCSVWriter(w io.Writer) *summaryCSVWriter {
+	return &summaryCSVWriter{
+		cw: csv.NewWriter(w),
+	}
+}
+
+// Write escreve uma linha do relatório
+func (sw *summaryCSVWriter) Write(app AppSummary) error {
+	if err := sw.writeHeader(); err != nil {
+		return err
+	}
+
+	return sw.cw.Write([]string{
+		app.UserID,
+		app.UserName,
+		strconv.Itoa(app.TotalCount),
+		app.TotalCost.String(),
+		app.TotalCost.Currency(),
+	})
+}
+
+// Flush envia o que foi escrito. O cabeçalho é escrito mesmo sem linhas
+func (sw *summaryCSVWriter) Flush() error {
+	if err := sw.writeHeader(); err != nil {
+		return err
+	}
+
+	sw.cw.Flush()
+	return sw.cw.Error()
+}
+
+func (sw *summaryCSVWriter) writeHeader() error {
+	if sw.header {
+		return nil
+	}
+	sw.header = true
+
+	return sw.cw.Write(summaryCSVColumns)
+}
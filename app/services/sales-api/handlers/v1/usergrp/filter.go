@@ -3,14 +3,32 @@ package usergrp
 import (
 	"net/http"
 	"net/mail"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/vitoraalmeida/service/business/core/user"
 	"github.com/vitoraalmeida/service/business/cview/user/summary"
+	"github.com/vitoraalmeida/service/business/data/expr"
+	"github.com/vitoraalmeida/service/business/data/money"
 	"github.com/vitoraalmeida/service/business/sys/validate"
 )
 
+// parseCurrency retorna a moeda para a qual os valores devem ser convertidos,
+// informada no parâmetro currency. Retorna vazio se não informada
+func parseCurrency(r *http.Request) (string, error) {
+	currency := strings.ToUpper(r.URL.Query().Get("currency"))
+	if currency == "" {
+		return "", nil
+	}
+
+	if err := money.ValidateCurrency(currency); err != nil {
+		return "", validate.NewFieldsError("currency", err)
+	}
+
+	return currency, nil
+}
+
 // Verifica se a Query string contém campos que indicam filtros de resultados
 func parseFilter(r *http.Request) (user.QueryFilter, error) {
 	values := r.URL.Query()
@@ -53,6 +71,13 @@ func parseFilter(r *http.Request) (user.QueryFilter, error) {
 		filter.WithName(name)
 	}
 
+	// condições livres no formato filter=campo<op>valor,...
+	exp, err := expr.Parse(r, user.FilterFields)
+	if err != nil {
+		return user.QueryFilter{}, err
+	}
+	filter.WithExpression(exp)
+
 	// utiliza a validação com base nas tags de filtro adicionadas em
 	// business/core/user/filter
 	if err := filter.Validate(); err != nil {
@@ -81,5 +106,11 @@ func parseSummaryFilter(r *http.Request) (summary.QueryFilter, error) {
 		filter.WithUserName(userName)
 	}
 
+	exp, err := expr.Parse(r, summary.FilterFields)
+	if err != nil {
+		return summary.QueryFilter{}, err
+	}
+	filter.WithExpression(exp)
+
 	return filter, nil
 }
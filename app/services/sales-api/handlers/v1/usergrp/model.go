@@ -7,6 +7,7 @@ import (
 
 	"github.com/vitoraalmeida/service/business/core/user"
 	"github.com/vitoraalmeida/service/business/cview/user/summary"
+	"github.com/vitoraalmeida/service/business/data/money"
 	"github.com/vitoraalmeida/service/business/sys/validate"
 )
 
@@ -159,10 +160,10 @@ func (app AppUpdateUser) Validate() error {
 
 // AppSummary representa informação sobre um usuário e seus produtos relacionados
 type AppSummary struct {
-	UserID     string  `json:"userID"`
-	UserName   string  `json:"userName"`
-	TotalCount int     `json:"totalCount"`
-	TotalCost  float64 `json:"totalCost"`
+	UserID     string      `json:"userID"`
+	UserName   string      `json:"userName"`
+	TotalCount int         `json:"totalCount"`
+	TotalCost  money.Money `json:"totalCost"`
 }
 
 func toAppSummary(smm summary.Summary) AppSummary {
@@ -5,9 +5,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/core/exchange"
 	"github.com/vitoraalmeida/service/business/core/user"
+	"github.com/vitoraalmeida/service/business/cview/user/summary"
+	"github.com/vitoraalmeida/service/business/data/order"
+	"github.com/vitoraalmeida/service/business/sys/validate"
+	"github.com/vitoraalmeida/service/business/web/auth"
 	v1 "github.com/vitoraalmeida/service/business/web/v1"
 	"github.com/vitoraalmeida/service/business/web/v1/paging"
 	"github.com/vitoraalmeida/service/foundation/web"
@@ -15,13 +22,21 @@ import (
 
 // Handlers manages the set of user endpoints.
 type Handlers struct {
-	user *user.Core
+	user     *user.Core
+	summary  *summary.Core
+	exchange *exchange.Core // converte os totais do resumo para outra moeda
+	auth     *auth.Auth     // restringe a consulta por ID ao próprio usuário ou a administradores
+	paging   paging.Config  // limites de paginação das rotas de consulta
 }
 
 // New constructs a handlers for route access.
-func New(user *user.Core) *Handlers {
+func New(user *user.Core, summary *summary.Core, exchange *exchange.Core, auth *auth.Auth, pagingCfg paging.Config) *Handlers {
 	return &Handlers{
-		user: user,
+		user:     user,
+		summary:  summary,
+		exchange: exchange,
+		auth:     auth,
+		paging:   pagingCfg,
 	}
 }
 
@@ -107,14 +122,9 @@ func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Re
 // 	return web.Respond(ctx, w, nil, http.StatusNoContent)
 // }
 
-// Query retorna uma lista de usuários paginada
+// Query retorna uma lista de usuários paginada. Se o parâmetro cursor for
+// informado, usa paginação por cursor ao invés de número de página
 func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	// Faz o parsing por informações de paginção
-	page, err := paging.ParseRequest(r)
-	if err != nil {
-		return err
-	}
-
 	// Faz o parsing por informações de filtragem de resultados
 	// filter é um objeto com informações de usuário que podem
 	// ser usadas para filtrar resultados
@@ -129,6 +139,16 @@ func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Req
 		return err
 	}
 
+	if paging.IsCursorRequest(r) {
+		return h.queryByCursor(ctx, w, r, filter, orderBy)
+	}
+
+	// Faz o parsing por informações de paginção
+	page, err := paging.ParseRequest(r, h.paging)
+	if err != nil {
+		return err
+	}
+
 	// executa a query no banco com base nas informações passadas
 	users, err := h.user.Query(ctx, filter, orderBy, page.Number, page.RowsPerPage)
 	if err != nil {
@@ -148,20 +168,231 @@ func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Req
 	return web.Respond(ctx, w, paging.NewResponse(items, total, page.Number, page.RowsPerPage), http.StatusOK)
 }
 
-// considera que o id está vindo do token JWT
-// QueryByID returns a user by its ID.
-// func (h *Handlers) QueryByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-// 	id := auth.GetUserID(ctx)
+// queryByCursor retorna uma página de usuários a partir de um cursor
+func (h *Handlers) queryByCursor(ctx context.Context, w http.ResponseWriter, r *http.Request, filter user.QueryFilter, orderBy order.By) error {
+	page, err := paging.ParseCursorRequest(r, orderBy, h.paging)
+	if err != nil {
+		return err
+	}
 
-// 	usr, err := h.user.QueryByID(ctx, id)
-// 	if err != nil {
-// 		switch {
-// 		case errors.Is(err, user.ErrNotFound):
-// 			return v1.NewRequestError(err, http.StatusNotFound)
-// 		default:
-// 			return fmt.Errorf("querybyid: id[%s]: %w", id, err)
-// 		}
-// 	}
+	users, cursorPage, err := h.user.QueryByCursor(ctx, filter, orderBy, page.Cursor, page.RowsPerPage)
+	if err != nil {
+		return fmt.Errorf("querybycursor: %w", err)
+	}
 
-// 	return web.Respond(ctx, w, toAppUser(usr), http.StatusOK)
-// }
+	items := make([]AppUser, len(users))
+	for i, usr := range users {
+		items[i] = toAppUser(usr)
+	}
+
+	return web.Respond(ctx, w, paging.NewCursorResponse(items, cursorPage, page.RowsPerPage), http.StatusOK)
+}
+
+// QueryByID retorna um usuário pelo seu ID. A resposta carrega ETag e
+// Last-Modified para que clientes possam fazer requisições condicionais. Quem
+// não é administrador só consulta a si mesmo, e os demais usuários são
+// respondidos como não encontrados, como na busca
+func (h *Handlers) QueryByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	id, err := uuid.Parse(web.Param(r, "user_id"))
+	if err != nil {
+		return validate.NewFieldsError("user_id", err)
+	}
+
+	allowed, err := h.canView(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return v1.NewRequestError(user.ErrNotFound, http.StatusNotFound)
+	}
+
+	usr, err := h.user.QueryByID(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrNotFound):
+			return v1.NewRequestError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querybyid: id[%s]: %w", id, err)
+		}
+	}
+
+	return web.RespondConditional(ctx, w, r, toAppUser(usr), usr.DateUpdated, http.StatusOK)
+}
+
+// canView informa se o chamador pode consultar o usuário: administradores
+// consultam qualquer usuário e os demais apenas a si mesmos
+func (h *Handlers) canView(ctx context.Context, userID uuid.UUID) (bool, error) {
+	claims := auth.GetClaims(ctx)
+
+	err := h.auth.Authorize(ctx, claims, auth.RuleAdminOnly)
+	switch {
+	case err == nil:
+		return true, nil
+	case !errors.Is(err, auth.ErrForbidden):
+		return false, fmt.Errorf("canview: %w", err)
+	}
+
+	callerID, err := auth.GetUserID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return callerID == userID, nil
+}
+
+// QuerySummary retorna uma lista paginada com o resumo dos produtos de cada
+// usuário. Os custos de produtos em moedas diferentes são somados na moeda
+// padrão, ou na moeda informada no parâmetro currency
+func (h *Handlers) QuerySummary(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	page, err := paging.ParseRequest(r, h.paging)
+	if err != nil {
+		return err
+	}
+
+	currency, err := parseCurrency(r)
+	if err != nil {
+		return err
+	}
+
+	filter, err := parseSummaryFilter(r)
+	if err != nil {
+		return err
+	}
+
+	orderBy, err := parseSummaryOrder(r)
+	if err != nil {
+		return err
+	}
+
+	smms, err := h.summary.Query(ctx, filter, orderBy, page.Number, page.RowsPerPage)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	rates, err := h.summaryRates(ctx, currency)
+	if err != nil {
+		return err
+	}
+
+	for i, smm := range smms {
+		if smms[i], err = convertSummary(smm, rates, currency); err != nil {
+			return err
+		}
+	}
+
+	items := make([]AppSummary, len(smms))
+	for i, smm := range smms {
+		items[i] = toAppSummary(smm)
+	}
+
+	total, err := h.summary.Count(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("count: %w", err)
+	}
+
+	return web.Respond(ctx, w, paging.NewResponse(items, total, page.Number, page.RowsPerPage), http.StatusOK)
+}
+
+// ExportSummary envia o relatório de resumo dos usuários em CSV, com os mesmos
+// filtros e ordenação de QuerySummary, mas sem paginação. As linhas são
+// enviadas conforme são lidas do banco, então o relatório inteiro nunca fica
+// em memória. Se o cliente desconectar, a consulta é cancelada junto com o
+// contexto da requisição. Uma falha no meio da exportação é informada no
+// trailer Stream-Error
+func (h *Handlers) ExportSummary(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if format := r.URL.Query().Get("format"); format != "" && format != "csv" {
+		return validate.NewFieldsError("format", fmt.Errorf("format %q must be csv", format))
+	}
+
+	currency, err := parseCurrency(r)
+	if err != nil {
+		return err
+	}
+
+	filter, err := parseSummaryFilter(r)
+	if err != nil {
+		return err
+	}
+
+	orderBy, err := parseSummaryOrder(r)
+	if err != nil {
+		return err
+	}
+
+	rates, err := h.summaryRates(ctx, currency)
+	if err != nil {
+		return err
+	}
+
+	f := func(w io.Writer, flush func()) error {
+		sw := newSummaryCSVWriter(w)
+
+		var rows int
+		write := func(smm summary.Summary) error {
+			smm, err := convertSummary(smm, rates, currency)
+			if err != nil {
+				return err
+			}
+
+			if err := sw.Write(toAppSummary(smm)); err != nil {
+				return err
+			}
+
+			if rows++; rows%exportFlushRows == 0 {
+				if err := sw.Flush(); err != nil {
+					return err
+				}
+				flush()
+			}
+
+			return nil
+		}
+
+		if err := h.summary.QueryEach(ctx, filter, orderBy, write); err != nil {
+			return fmt.Errorf("export: %w", err)
+		}
+
+		if err := sw.Flush(); err != nil {
+			return err
+		}
+		flush()
+
+		return nil
+	}
+
+	return web.Stream(ctx, w, "text/csv", "usersummary.csv", f)
+}
+
+// summaryRates retorna as taxas de câmbio quando os totais devem ser
+// convertidos para a moeda informada. Sem moeda, retorna nil
+func (h *Handlers) summaryRates(ctx context.Context, currency string) (exchange.Rates, error) {
+	if currency == "" {
+		return nil, nil
+	}
+
+	rates, err := h.exchange.Rates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("rates: %w", err)
+	}
+
+	if _, exists := rates[currency]; !exists {
+		return nil, validate.NewFieldsError("currency", exchange.ErrNotFound)
+	}
+
+	return rates, nil
+}
+
+// convertSummary converte o total do resumo para a moeda informada. Sem
+// taxas, o resumo é mantido na moeda padrão
+func convertSummary(smm summary.Summary, rates exchange.Rates, currency string) (summary.Summary, error) {
+	if rates == nil {
+		return smm, nil
+	}
+
+	var err error
+	if smm.TotalCost, err = rates.Convert(smm.TotalCost, currency); err != nil {
+		return summary.Summary{}, fmt.Errorf("convert: userID[%s]: %w", smm.UserID, err)
+	}
+
+	return smm, nil
+}
@@ -13,12 +13,17 @@ import (
 
 	"github.com/ardanlabs/conf/v3"
 	"github.com/vitoraalmeida/service/app/services/sales-api/handlers"
+	"github.com/vitoraalmeida/service/business/cview/user/summary"
+	"github.com/vitoraalmeida/service/business/cview/user/summary/stores/summarydb"
 	database "github.com/vitoraalmeida/service/business/sys/database/pgx"
 	"github.com/vitoraalmeida/service/business/web/auth"
 	"github.com/vitoraalmeida/service/business/web/v1/debug"
+	"github.com/vitoraalmeida/service/business/web/v1/mid"
 	"github.com/vitoraalmeida/service/foundation/keystore"
 	"github.com/vitoraalmeida/service/foundation/logger"
+	"github.com/vitoraalmeida/service/foundation/tracer"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // é alterado por ldflags
@@ -28,7 +33,16 @@ func main() {
 	// construímos nosso logger e passaremos ele concretamente para os componentes
 	// que precisarmos. Não devemos adicionar loggers em contexts, pois assim
 	// acabamos passando para todos os lugares de forma desnecessária
-	log, err := logger.New("SALES-API")
+	// o nível pode ser alterado com a aplicação em execução pelo endpoint
+	// /debug/loglevel. O nível configurado é aplicado depois da leitura da
+	// configuração
+	level := logger.NewLevel(zapcore.InfoLevel)
+
+	// a amostragem segue o padrão de produção do zap até a configuração ser
+	// lida
+	sampler := logger.NewSampler(100, 100, time.Second)
+
+	log, err := logger.New("SALES-API", level, sampler)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -39,7 +53,7 @@ func main() {
 	// Applications should take care to call Sync before exiting.
 	defer log.Sync()
 
-	if err := run(log); err != nil {
+	if err := run(log, level, sampler); err != nil {
 		log.Errorw("startup", "ERROR", err)
 		log.Sync()
 		os.Exit(1)
@@ -47,7 +61,7 @@ func main() {
 }
 
 // coordena a inicialização e desligamento do sistema
-func run(log *zap.SugaredLogger) error {
+func run(log *zap.SugaredLogger, level *logger.Level, sampler *logger.Sampler) error {
 
 	// -------------------------------------------------------------------------
 	// GOMAXPROCS
@@ -76,6 +90,8 @@ func run(log *zap.SugaredLogger) error {
 			APIHost         string        `conf:"default:0.0.0.0:3000"`
 			// definir o host do serviço de debug em outro ip/rota para impossbilitar o acesso externo
 			DebugHost string `conf:"default:0.0.0.0:4000"`
+			// cabeçalho de onde o ID da requisição é lido (X-Request-ID ou traceparent)
+			TraceIDHeader string `conf:"default:X-Request-ID"`
 			//adicionar noprint no fim da tag de configuração caso não queira que essa info vá para o log
 			//DebugHost       string        `conf:"default:0.0.0.0:4000,noprint"`
 			//adicionar mask no fim da tag de configuração caso queira que apareça, mas mascarado
@@ -89,6 +105,23 @@ func run(log *zap.SugaredLogger) error {
 			MaxIdleConns int    `conf:"default:2"`
 			MaxOpenConns int    `conf:"default:0"`
 			DisableTLS   bool   `conf:"default:true"`
+			// queries mais demoradas que o limite são registradas em nível Warn
+			SlowQueryThreshold time.Duration `conf:"default:200ms"`
+			LogQueryValues     bool          `conf:"default:false"`
+		}
+		// nível dos logs e níveis por pacote, como userdb:debug;pgx:warn. A
+		// amostragem escreve, por segundo, as primeiras SamplingInitial
+		// entradas iguais e depois uma a cada SamplingThereafter. Logs de
+		// requisições são amostrados por trace_id, mantendo a requisição
+		// inteira. Erros e requisições mais lentas que SlowRequest sempre são
+		// registrados e as rotas em SkipRoutes só são registradas nesses casos
+		Log struct {
+			Level              string `conf:"default:info"`
+			Overrides          map[string]string
+			SamplingInitial    int           `conf:"default:100"`
+			SamplingThereafter int           `conf:"default:100"`
+			SlowRequest        time.Duration `conf:"default:1s"`
+			SkipRoutes         []string
 		}
 		// informações para lidar com autenticação
 		Auth struct {
@@ -96,6 +129,20 @@ func run(log *zap.SugaredLogger) error {
 			ActiveKID  string `conf:"default:54bb2165-71e1-41a6-af3e-7da4a0e1e2c1"` // nome da key PEM que será pré-definida
 			Issuer     string `conf:"default:service project"`                      // define quem é o criador do token
 		}
+		// exportação dos traces: otlp, zipkin, stdout ou none. Sem endpoint,
+		// usa o endereço padrão do exporter
+		Tracing struct {
+			Exporter    string `conf:"default:none"`
+			Endpoint    string
+			ServiceName string  `conf:"default:sales-api"`
+			Probability float64 `conf:"default:0.05"`
+		}
+		// resumo de usuários lido de uma cópia materializada, atualizada em
+		// segundo plano a cada RefreshInterval
+		Summary struct {
+			Materialized    bool          `conf:"default:false"`
+			RefreshInterval time.Duration `conf:"default:1m"`
+		}
 	}{
 		Version: conf.Version{
 			Build: build,
@@ -119,6 +166,30 @@ func run(log *zap.SugaredLogger) error {
 		return fmt.Errorf("parsing config: %w", err)
 	}
 
+	// o ticker do worker de atualização não aceita intervalos não positivos
+	if cfg.Summary.RefreshInterval <= 0 {
+		return fmt.Errorf("validating config: summary refresh interval must be greater than zero, got %s", cfg.Summary.RefreshInterval)
+	}
+
+	// -------------------------------------------------------------------------
+	// Nível dos logs
+
+	lvl, err := logger.ParseLevel(cfg.Log.Level)
+	if err != nil {
+		return fmt.Errorf("parsing log level: %w", err)
+	}
+	level.SetLevel(lvl)
+
+	for pkg, name := range cfg.Log.Overrides {
+		lvl, err := logger.ParseLevel(name)
+		if err != nil {
+			return fmt.Errorf("parsing log level override %s: %w", pkg, err)
+		}
+		level.SetOverride(pkg, lvl)
+	}
+
+	sampler.Set(cfg.Log.SamplingInitial, cfg.Log.SamplingThereafter, time.Second)
+
 	// -------------------------------------------------------------------------
 	// App Starting
 
@@ -145,6 +216,9 @@ func run(log *zap.SugaredLogger) error {
 		MaxIdleConns: cfg.DB.MaxIdleConns,
 		MaxOpenConns: cfg.DB.MaxOpenConns,
 		DisableTLS:   cfg.DB.DisableTLS,
+
+		SlowQueryThreshold: cfg.DB.SlowQueryThreshold,
+		LogQueryValues:     cfg.DB.LogQueryValues,
 	})
 	if err != nil {
 		return fmt.Errorf("connecting to db: %w", err)
@@ -176,6 +250,56 @@ func run(log *zap.SugaredLogger) error {
 		return fmt.Errorf("constructing auth: %w", err)
 	}
 
+	// -------------------------------------------------------------------------
+	// Inicia suporte a tracing
+
+	log.Infow("startup", "status", "initializing tracing support", "exporter", cfg.Tracing.Exporter)
+
+	traceProvider, err := tracer.New(tracer.Config{
+		ServiceName: cfg.Tracing.ServiceName,
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Probability: cfg.Tracing.Probability,
+	})
+	if err != nil {
+		return fmt.Errorf("starting tracing: %w", err)
+	}
+	defer func() {
+		// envia os spans que ainda estão pendentes antes de encerrar
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := traceProvider.Shutdown(ctx); err != nil {
+			log.Errorw("shutdown", "status", "stopping tracing support", "ERROR", err)
+		}
+	}()
+
+	// -------------------------------------------------------------------------
+	// Inicia a atualização do resumo materializado
+
+	// o contexto é cancelado no desligamento, interrompendo o worker
+	workerCtx, workerCancel := context.WithCancel(context.Background())
+	defer workerCancel()
+
+	var smmWorker *summary.RefreshWorker
+	if cfg.Summary.Materialized {
+		log.Infow("startup", "status", "summary refresh worker started", "interval", cfg.Summary.RefreshInterval)
+
+		smmWorker = summary.NewRefreshWorker(log, summarydb.NewMaterializedStore(log, db), cfg.Summary.RefreshInterval)
+
+		workerDone := make(chan struct{})
+		go func() {
+			defer close(workerDone)
+			smmWorker.Run(workerCtx)
+		}()
+
+		// aguarda a atualização em andamento terminar antes de fechar o banco
+		defer func() {
+			workerCancel()
+			<-workerDone
+			log.Infow("shutdown", "status", "summary refresh worker stopped")
+		}()
+	}
+
 	// -------------------------------------------------------------------------
 	// Inicia serviço de debug
 
@@ -185,7 +309,7 @@ func run(log *zap.SugaredLogger) error {
 	// caso a goroutine principal morra, não tem problema esta fica orfã, pois
 	// ela apenas realiza leitura
 	go func() {
-		if err := http.ListenAndServe(cfg.Web.DebugHost, debug.Mux(build, log, db)); err != nil {
+		if err := http.ListenAndServe(cfg.Web.DebugHost, debug.Mux(build, log, level, db, smmWorker)); err != nil {
 			log.Errorw("shutdown", "status", "debug v1 router closed", "host", cfg.Web.DebugHost, "ERROR", err)
 		}
 	}()
@@ -207,6 +331,14 @@ func run(log *zap.SugaredLogger) error {
 		Log:      log,
 		Auth:     auth,
 		DB:       db,
+		Tracer:   traceProvider.Tracer("sales-api"),
+
+		TraceIDHeader: cfg.Web.TraceIDHeader,
+		Logging: mid.LoggerConfig{
+			SlowRequest: cfg.Log.SlowRequest,
+			SkipRoutes:  cfg.Log.SkipRoutes,
+		},
+		SummaryMaterialized: cfg.Summary.Materialized,
 	})
 
 	// cria uma instância de http.Server customizada com os valores de configuração
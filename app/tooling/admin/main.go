@@ -2,12 +2,25 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"os"
 	"time"
 
+	"github.com/vitoraalmeida/service/business/core/category"
+	"github.com/vitoraalmeida/service/business/core/category/stores/categorydb"
+	"github.com/vitoraalmeida/service/business/core/exchange"
+	"github.com/vitoraalmeida/service/business/core/exchange/stores/exchangedb"
+	"github.com/vitoraalmeida/service/business/core/inventory"
+	"github.com/vitoraalmeida/service/business/core/inventory/stores/inventorydb"
+	"github.com/vitoraalmeida/service/business/core/product"
+	"github.com/vitoraalmeida/service/business/core/product/stores/productdb"
+	"github.com/vitoraalmeida/service/business/core/user"
+	"github.com/vitoraalmeida/service/business/core/user/stores/userdb"
 	"github.com/vitoraalmeida/service/business/data/dbmigrate"
 	database "github.com/vitoraalmeida/service/business/sys/database/pgx"
+	"go.uber.org/zap"
 )
 
 func main() {
@@ -27,15 +40,31 @@ func run() error {
 		DisableTLS:   true,
 	}
 
-	if err := migrate(cfg); err != nil {
-		return fmt.Errorf("migrate: %w", err)
+	// sem argumentos mantém o comportamento padrão de migrar e popular o banco
+	if len(os.Args) < 2 {
+		if err := migrate(cfg); err != nil {
+			return fmt.Errorf("migrate: %w", err)
+		}
+
+		if err := seed(cfg); err != nil {
+			return fmt.Errorf("seed: %w", err)
+		}
+
+		return nil
 	}
 
-	if err := seed(cfg); err != nil {
-		return fmt.Errorf("seed: %w", err)
+	switch os.Args[1] {
+	case "migrate":
+		return migrate(cfg)
+	case "seed":
+		return seed(cfg)
+	case "reconcile":
+		// "reconcile fix" corrige as divergências encontradas
+		fix := len(os.Args) > 2 && os.Args[2] == "fix"
+		return reconcile(cfg, fix)
 	}
 
-	return nil
+	return fmt.Errorf("unknown command %q, use migrate, seed or reconcile [fix]", os.Args[1])
 }
 
 func migrate(cfg database.Config) error {
@@ -73,3 +102,53 @@ func seed(cfg database.Config) error {
 	fmt.Println("seed data complete")
 	return nil
 }
+
+// reconcile compara a quantidade de cada produto com a soma do histórico de
+// estoque e lista as divergências. Com fix, a quantidade dos produtos é
+// substituída pelo valor do histórico
+func reconcile(cfg database.Config, fix bool) error {
+	db, err := database.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// os logs das queries não interessam na saída da ferramenta
+	log := zap.NewNop().Sugar()
+
+	usrCore := user.NewCore(userdb.NewStore(log, db))
+	catCore := category.NewCore(log, categorydb.NewStore(log, db))
+	exchCore := exchange.NewCore(log, exchangedb.NewStore(log, db))
+	prdCore := product.NewCore(log, usrCore, catCore, exchCore, productdb.NewStore(log, db))
+	invCore := inventory.NewCore(log, prdCore, inventorydb.NewStore(log, db))
+
+	var drifts []inventory.Drift
+	switch fix {
+	case true:
+		drifts, err = invCore.Reconcile(ctx)
+	default:
+		drifts, err = invCore.Drift(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("reconcile: %w", err)
+	}
+
+	if len(drifts) == 0 {
+		fmt.Println("no drift found")
+		return nil
+	}
+
+	for _, d := range drifts {
+		fmt.Printf("product %s: quantity %d, ledger %d, drift %d\n", d.ProductID, d.Quantity, d.LedgerQuantity, d.Quantity-d.LedgerQuantity)
+	}
+
+	if fix {
+		fmt.Printf("%d products reconciled\n", len(drifts))
+		return nil
+	}
+
+	return errors.New("drift found, run reconcile fix to correct it")
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// traceID usado quando a requisição não tem contexto
+const zeroTraceID = "00000000-0000-0000-0000-000000000000"
+
+// formatos de data aceitos no campo ts e nos filtros de tempo. O primeiro é o
+// usado pelo foundation/logger
+var timeLayouts = []string{
+	"2006-01-02T15:04:05.000Z0700",
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// entry é uma linha do log estruturado. Os campos conhecidos são extraídos de
+// forma tolerante: campos ausentes ou de outro tipo ficam vazios em vez de
+// interromper a leitura
+type entry struct {
+	fields  map[string]any
+	service string
+	ts      time.Time // zero quando ausente ou inválido
+	level   string
+	traceID string
+	caller  string
+	msg     string
+}
+
+// parseEntry converte uma linha em entry. Retorna falso quando a linha não é
+// um objeto JSON
+func parseEntry(line string) (entry, bool) {
+	m := make(map[string]any)
+	if err := json.Unmarshal([]byte(line), &m); err != nil {
+		return entry{}, false
+	}
+
+	e := entry{
+		fields:  m,
+		service: field(m, "service"),
+		level:   strings.ToLower(field(m, "level")),
+		traceID: field(m, "trace_id"),
+		caller:  field(m, "caller"),
+		msg:     field(m, "msg"),
+	}
+
+	switch ts := m["ts"].(type) {
+	case string:
+		e.ts, _ = parseTime(ts)
+	case float64:
+		// encoder padrão do zap em produção: segundos desde a época
+		e.ts = time.Unix(0, int64(ts*float64(time.Second)))
+	}
+
+	return e, true
+}
+
+// hasTrace informa se a entrada pertence a uma requisição
+func (e entry) hasTrace() bool {
+	return e.traceID != "" && e.traceID != zeroTraceID
+}
+
+// zapLevel retorna o nível da entrada. Falso quando o nível é desconhecido
+func (e entry) zapLevel() (zapcore.Level, bool) {
+	lvl, err := zapcore.ParseLevel(e.level)
+	if err != nil {
+		return 0, false
+	}
+	return lvl, true
+}
+
+// field retorna o valor do campo como texto, vazio quando ausente
+func field(m map[string]any, key string) string {
+	switch v := m[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// parseTime converte um texto em data usando os formatos aceitos
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// filter define quais entradas são mostradas. Campos vazios não filtram
+type filter struct {
+	service  string
+	level    *zapcore.Level
+	traceIDs map[string]bool
+	since    time.Time
+	until    time.Time
+}
+
+// newFilter constrói o filtro a partir das flags. since aceita uma data ou uma
+// duração relativa ao momento atual, como 15m
+func newFilter(service string, level string, traceIDs string, since string, until string, now time.Time) (filter, error) {
+	f := filter{
+		service: strings.ToLower(service),
+	}
+
+	if level != "" {
+		lvl, err := zapcore.ParseLevel(level)
+		if err != nil {
+			return filter{}, fmt.Errorf("invalid level %q", level)
+		}
+		f.level = &lvl
+	}
+
+	if traceIDs != "" {
+		f.traceIDs = make(map[string]bool)
+		for _, id := range strings.Split(traceIDs, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				f.traceIDs[id] = true
+			}
+		}
+	}
+
+	if since != "" {
+		t, err := parseTimeOrAgo(since, now)
+		if err != nil {
+			return filter{}, fmt.Errorf("since: %w", err)
+		}
+		f.since = t
+	}
+
+	if until != "" {
+		t, err := parseTimeOrAgo(until, now)
+		if err != nil {
+			return filter{}, fmt.Errorf("until: %w", err)
+		}
+		f.until = t
+	}
+
+	return f, nil
+}
+
+// active informa se algum filtro foi definido
+func (f filter) active() bool {
+	return f.service != "" || f.level != nil || f.traceIDs != nil || !f.since.IsZero() || !f.until.IsZero()
+}
+
+// match informa se a entrada passa pelo filtro. Entradas sem o campo usado
+// por um filtro não passam por ele, exceto o nível: um nível desconhecido é
+// mostrado para não esconder linhas fora do padrão
+func (f filter) match(e entry) bool {
+	if f.service != "" && strings.ToLower(e.service) != f.service {
+		return false
+	}
+
+	if f.level != nil {
+		if lvl, ok := e.zapLevel(); ok && lvl < *f.level {
+			return false
+		}
+	}
+
+	if f.traceIDs != nil && !f.traceIDs[e.traceID] {
+		return false
+	}
+
+	if !f.since.IsZero() && (e.ts.IsZero() || e.ts.Before(f.since)) {
+		return false
+	}
+
+	if !f.until.IsZero() && (e.ts.IsZero() || e.ts.After(f.until)) {
+		return false
+	}
+
+	return true
+}
+
+// parseTimeOrAgo aceita uma data ou uma duração, que é subtraída de now
+func parseTimeOrAgo(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	return parseTime(s)
+}
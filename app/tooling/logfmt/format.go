@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// códigos ANSI usados para colorir o nível
+const (
+	colorReset  = "\x1b[0m"
+	colorGray   = "\x1b[90m"
+	colorBlue   = "\x1b[34m"
+	colorYellow = "\x1b[33m"
+	colorRed    = "\x1b[31m"
+)
+
+// campos mostrados no início de toda linha, nessa ordem
+var headerFields = map[string]bool{
+	"service":  true,
+	"ts":       true,
+	"level":    true,
+	"trace_id": true,
+	"caller":   true,
+	"msg":      true,
+}
+
+// formatter converte entradas em texto legível
+type formatter struct {
+	color  bool
+	fields []string // campos extras mostrados. Nulo mostra todos
+}
+
+// format constrói a linha com os campos principais numa ordem fixa seguidos
+// dos demais campos
+func (f formatter) format(e entry) string {
+	var b strings.Builder
+
+	traceID := e.traceID
+	if traceID == "" {
+		traceID = zeroTraceID
+	}
+
+	fmt.Fprintf(&b, "%s: %s: %s: %s: %s: %s",
+		orDash(e.service),
+		orDash(field(e.fields, "ts")),
+		f.level(e.level),
+		traceID,
+		orDash(e.caller),
+		e.msg,
+	)
+
+	keys := f.fields
+	if keys == nil {
+		for k := range e.fields {
+			if !headerFields[k] {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+	}
+
+	for _, k := range keys {
+		v, ok := e.fields[k]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&b, ": %s[%v]", k, v)
+	}
+
+	return b.String()
+}
+
+// level retorna o nível colorido quando a saída permite
+func (f formatter) level(level string) string {
+	level = orDash(level)
+	if !f.color {
+		return level
+	}
+
+	color := colorReset
+	switch level {
+	case "debug":
+		color = colorGray
+	case "info":
+		color = colorBlue
+	case "warn":
+		color = colorYellow
+	case "error", "dpanic", "panic", "fatal":
+		color = colorRed
+	}
+
+	return color + level + colorReset
+}
+
+// parseFields converte a lista de campos separados por vírgula
+func parseFields(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	fields := []string{}
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields
+}
+
+// useColor decide se a saída será colorida. auto colore apenas quando a saída
+// é um terminal
+func useColor(mode string) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		fi, err := os.Stdout.Stat()
+		if err != nil {
+			return false, nil
+		}
+		return fi.Mode()&os.ModeCharDevice != 0, nil
+	default:
+		return false, fmt.Errorf("invalid color mode %q, use auto, always or never", mode)
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
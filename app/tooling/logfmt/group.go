@@ -0,0 +1,97 @@
+package main
+
+import (
+	"time"
+)
+
+// mensagem que encerra os logs de uma requisição, escrita por mid.Logger
+const requestCompleted = "request completed"
+
+// grouper agrupa as entradas por trace_id para que os logs de uma requisição
+// apareçam juntos, mesmo intercalados com os de outras requisições. Um grupo é
+// liberado quando a requisição termina ou quando fica sem novas entradas por
+// idle, o que cobre traces sem o log de término
+type grouper struct {
+	idle    time.Duration
+	groups  map[string]*traceGroup
+	ordered []string // ordem de chegada dos traces
+}
+
+type traceGroup struct {
+	entries  []entry
+	lastSeen time.Time
+}
+
+func newGrouper(idle time.Duration) *grouper {
+	return &grouper{
+		idle:   idle,
+		groups: make(map[string]*traceGroup),
+	}
+}
+
+// add adiciona a entrada e retorna as entradas que podem ser mostradas.
+// Entradas sem trace são retornadas imediatamente
+func (g *grouper) add(e entry, now time.Time) []entry {
+	out := g.expire(now)
+
+	if !e.hasTrace() {
+		return append(out, e)
+	}
+
+	grp, ok := g.groups[e.traceID]
+	if !ok {
+		grp = &traceGroup{}
+		g.groups[e.traceID] = grp
+		g.ordered = append(g.ordered, e.traceID)
+	}
+	grp.entries = append(grp.entries, e)
+	grp.lastSeen = now
+
+	if e.msg == requestCompleted {
+		out = append(out, grp.entries...)
+		g.remove(e.traceID)
+	}
+
+	return out
+}
+
+// expire libera os grupos sem novas entradas há mais de idle
+func (g *grouper) expire(now time.Time) []entry {
+	var out []entry
+	ordered := g.ordered[:0]
+	for _, id := range g.ordered {
+		grp := g.groups[id]
+		if now.Sub(grp.lastSeen) < g.idle {
+			ordered = append(ordered, id)
+			continue
+		}
+		out = append(out, grp.entries...)
+		delete(g.groups, id)
+	}
+	g.ordered = ordered
+
+	return out
+}
+
+// flush libera todos os grupos pendentes, na ordem de chegada
+func (g *grouper) flush() []entry {
+	var out []entry
+	for _, id := range g.ordered {
+		out = append(out, g.groups[id].entries...)
+	}
+
+	g.groups = make(map[string]*traceGroup)
+	g.ordered = nil
+
+	return out
+}
+
+func (g *grouper) remove(traceID string) {
+	delete(g.groups, traceID)
+	for i, id := range g.ordered {
+		if id == traceID {
+			g.ordered = append(g.ordered[:i], g.ordered[i+1:]...)
+			return
+		}
+	}
+}
@@ -1,85 +1,174 @@
 // Recebe log estruturado e devolve em formato legível para humanos
+//
+// O log é lido da entrada padrão ou do arquivo passado como argumento:
+//
+//	logfmt -service=sales-api -level=warn -fields=statuscode,since
+//	logfmt -trace=<trace_id> -since=15m -group app.log
+//	logfmt -f app.log
+//
+// Subcomandos:
+//
+//	logfmt stats [-top=10] [arquivo]      latência por rota, status codes e traces mais lentos
+//	logfmt trace <trace_id> [arquivo]     todas as linhas de uma requisição com o tempo relativo
 package main
 
 import (
-	"bufio"
-	"encoding/json"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
-	"strings"
+	"os/signal"
+	"time"
 )
 
-var service string
+// tempo sem novas entradas depois do qual um grupo é mostrado mesmo sem o
+// término da requisição
+const groupIdle = 5 * time.Second
+
+var (
+	service string
+	level   string
+	traceID string
+	since   string
+	until   string
+	fields  string
+	color   string
+	group   bool
+	follow  bool
+)
 
 func init() {
 	// vai filtrar os logs estruturados que tiverem o campo "service"
 	flag.StringVar(&service, "service", "", "filtra qual serviço deve ser convertido para texto legível")
+	flag.StringVar(&level, "level", "", "nível mínimo dos logs mostrados (debug, info, warn, error)")
+	flag.StringVar(&traceID, "trace", "", "mostra apenas os logs dos trace_id informados, separados por vírgula")
+	flag.StringVar(&since, "since", "", "mostra logs a partir de uma data (2006-01-02T15:04:05) ou de uma duração atrás (15m)")
+	flag.StringVar(&until, "until", "", "mostra logs até uma data ou até uma duração atrás")
+	flag.StringVar(&fields, "fields", "", "campos extras mostrados, separados por vírgula. Vazio mostra todos")
+	flag.StringVar(&color, "color", "auto", "colore o nível: auto (apenas em terminal), always ou never")
+	flag.BoolVar(&group, "group", false, "agrupa as linhas de cada trace_id, mostrando a requisição inteira de uma vez")
+	flag.BoolVar(&follow, "f", false, "continua lendo o arquivo à medida que cresce, como tail -f")
 }
 
 func main() {
-	flag.Parse()
-	var b strings.Builder
+	var err error
+
+	switch {
+	case len(os.Args) > 1 && os.Args[1] == "stats":
+		err = runStats(os.Args[2:])
+	case len(os.Args) > 1 && os.Args[1] == "trace":
+		err = runTrace(os.Args[2:])
+	default:
+		flag.Parse()
+		err = run()
+	}
+
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func run() error {
+	flt, err := newFilter(service, level, traceID, since, until, time.Now())
+	if err != nil {
+		return err
+	}
 
-	service := strings.ToLower(service)
+	colored, err := useColor(color)
+	if err != nil {
+		return err
+	}
+
+	fmtr := formatter{
+		color:  colored,
+		fields: parseFields(fields),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	in, err := openInput(flag.Arg(0), follow)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	var grp *grouper
+	if group {
+		grp = newGrouper(groupIdle)
+	}
 
-	scanner := bufio.NewScanner(os.Stdin)
-	// scanner.Scan lê uma linha por vez
-	for scanner.Scan() {
-		s := scanner.Text()
+	lr := newLineReader(in, follow)
 
-		// o log que estamos utilizando está estruturado em json, então serializamos
-		// para um mapa com chaves do tipo string
-		m := make(map[string]any)
-		err := json.Unmarshal([]byte(s), &m)
+	// no modo follow um log parado não traz novas entradas, então os grupos
+	// ociosos são liberados enquanto esperamos
+	if grp != nil {
+		lr.wait = func() {
+			for _, e := range grp.expire(time.Now()) {
+				fmt.Println(fmtr.format(e))
+			}
+		}
+	}
+
+	for {
+		s, err := lr.next(ctx)
 		if err != nil {
-			if service == "" {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+
+		// o log que estamos utilizando está estruturado em json. Linhas em
+		// outro formato só são mostradas quando não há filtros
+		e, ok := parseEntry(s)
+		if !ok {
+			if !flt.active() {
 				fmt.Println(s)
 			}
 			continue
 		}
 
-		// checa se foi adicionado um filtro de serviço e para o serviço atual
-		// se ele for diferente do que queremos filtrar, ignora e busca o próximo
-		if service != "" && strings.ToLower(m["service"].(string)) != service {
+		if !flt.match(e) {
 			continue
 		}
 
-		// adiciona trace
-		traceID := "00000000-0000-0000-0000-000000000000"
-		if v, ok := m["trace_id"]; ok {
-			traceID = fmt.Sprintf("%v", v)
+		if grp == nil {
+			fmt.Println(fmtr.format(e))
+			continue
 		}
 
-		// constrói a string que será mostrada no log com os campos desejados
-		// na ordem desejada
-		b.Reset()
-		b.WriteString(fmt.Sprintf("%s: %s: %s: %s: %s: %s: ",
-			m["service"],
-			m["ts"],
-			m["level"],
-			traceID,
-			m["caller"],
-			m["msg"],
-		))
-
-		// Adiciona o restante dos campos do log estruturado
-		for k, v := range m {
-			switch k {
-			case "service", "ts", "level", "trace_id", "caller", "msg":
-				continue
-			}
+		for _, e := range grp.add(e, time.Now()) {
+			fmt.Println(fmtr.format(e))
+		}
+	}
 
-			b.WriteString(fmt.Sprintf("%s[%v]: ", k, v))
+	if grp != nil {
+		for _, e := range grp.flush() {
+			fmt.Println(fmtr.format(e))
 		}
+	}
 
-		// Write the new log format, removing the last :
-		out := b.String()
-		fmt.Println(out[:len(out)-2])
+	return nil
+}
+
+// openInput abre o arquivo informado ou usa a entrada padrão. O modo follow
+// só faz sentido para arquivos
+func openInput(path string, follow bool) (io.ReadCloser, error) {
+	if path == "" || path == "-" {
+		if follow {
+			return nil, errors.New("follow mode requires a file")
+		}
+		return io.NopCloser(os.Stdin), nil
 	}
 
-	if err := scanner.Err(); err != nil {
-		log.Println(err)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening log file: %w", err)
 	}
+
+	return f, nil
 }
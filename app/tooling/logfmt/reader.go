@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// intervalo entre as tentativas de leitura no modo follow
+const followInterval = 250 * time.Millisecond
+
+// lineReader lê o log linha a linha. No modo follow, ao chegar no fim do
+// arquivo espera por novas linhas, como tail -f, até o contexto ser cancelado
+type lineReader struct {
+	r      *bufio.Reader
+	follow bool
+	wait   func() // chamada a cada espera no modo follow, se definida
+}
+
+func newLineReader(r io.Reader, follow bool) *lineReader {
+	return &lineReader{
+		r:      bufio.NewReader(r),
+		follow: follow,
+	}
+}
+
+// next retorna a próxima linha sem a quebra de linha. Retorna io.EOF no fim
+// da leitura
+func (lr *lineReader) next(ctx context.Context) (string, error) {
+	var b strings.Builder
+
+	for {
+		s, err := lr.r.ReadString('\n')
+		b.WriteString(s)
+
+		switch {
+		case err == nil:
+			return strings.TrimRight(b.String(), "\r\n"), nil
+
+		case !errors.Is(err, io.EOF):
+			return "", err
+
+		// sem follow a última linha pode não ter quebra de linha
+		case !lr.follow:
+			if b.Len() > 0 {
+				return b.String(), nil
+			}
+			return "", io.EOF
+		}
+
+		// sem novas linhas, o restante do programa pode liberar o que estava
+		// esperando por elas
+		if lr.wait != nil {
+			lr.wait()
+		}
+
+		// linhas incompletas continuam sendo lidas na próxima tentativa
+		select {
+		case <-ctx.Done():
+			return "", io.EOF
+		case <-time.After(followInterval):
+		}
+	}
+}
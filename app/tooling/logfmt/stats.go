@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// requestStat é uma requisição terminada, montada a partir do log
+// "request completed" escrito por mid.Logger
+type requestStat struct {
+	traceID    string
+	route      string
+	statusCode int
+	since      time.Duration
+}
+
+// routeStats acumula as requisições de uma rota
+type routeStats struct {
+	route     string
+	durations []time.Duration
+	classes   [6]int // quantidade por classe de status: 1xx a 5xx. 0 é desconhecido
+}
+
+// runStats implementa o subcomando stats: lê um log e mostra os percentis de
+// latência por rota, a quantidade de requisições por status code e os traces
+// mais lentos
+func runStats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	service := fs.String("service", "", "considera apenas os logs do serviço informado")
+	top := fs.Int("top", 10, "quantidade de traces mais lentos mostrados")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "uso: logfmt stats [flags] [arquivo]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	in, err := openInput(fs.Arg(0), false)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	flt := filter{service: strings.ToLower(*service)}
+
+	var requests []requestStat
+	lr := newLineReader(in, false)
+	for {
+		s, err := lr.next(context.Background())
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+
+		e, ok := parseEntry(s)
+		if !ok || e.msg != requestCompleted || !flt.match(e) {
+			continue
+		}
+
+		requests = append(requests, newRequestStat(e))
+	}
+
+	if len(requests) == 0 {
+		fmt.Println("no completed requests found")
+		return nil
+	}
+
+	printRouteStats(os.Stdout, requests)
+	printStatusCodes(os.Stdout, requests)
+	printSlowest(os.Stdout, requests, *top)
+
+	return nil
+}
+
+// newRequestStat extrai os dados da requisição. Logs antigos, sem o campo
+// route, são agrupados pelo caminho sem a query string
+func newRequestStat(e entry) requestStat {
+	route := field(e.fields, "route")
+	if route == "" {
+		route, _, _ = strings.Cut(field(e.fields, "path"), "?")
+	}
+	if method := field(e.fields, "method"); method != "" {
+		route = method + " " + route
+	}
+
+	statusCode, _ := strconv.Atoi(field(e.fields, "statuscode"))
+
+	return requestStat{
+		traceID:    e.traceID,
+		route:      orDash(route),
+		statusCode: statusCode,
+		since:      parseSince(e.fields["since"]),
+	}
+}
+
+// parseSince converte a duração da requisição. O encoder de produção do zap
+// escreve durações em segundos, mas textos como 1.5ms também são aceitos
+func parseSince(v any) time.Duration {
+	switch v := v.(type) {
+	case float64:
+		return time.Duration(v * float64(time.Second))
+	case string:
+		d, _ := time.ParseDuration(v)
+		return d
+	}
+	return 0
+}
+
+func printRouteStats(w io.Writer, requests []requestStat) {
+	byRoute := make(map[string]*routeStats)
+	for _, r := range requests {
+		rs, ok := byRoute[r.route]
+		if !ok {
+			rs = &routeStats{route: r.route}
+			byRoute[r.route] = rs
+		}
+		rs.durations = append(rs.durations, r.since)
+
+		class := r.statusCode / 100
+		if class < 1 || class > 5 {
+			class = 0
+		}
+		rs.classes[class]++
+	}
+
+	routes := make([]*routeStats, 0, len(byRoute))
+	for _, rs := range byRoute {
+		sort.Slice(rs.durations, func(i, j int) bool { return rs.durations[i] < rs.durations[j] })
+		routes = append(routes, rs)
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if len(routes[i].durations) != len(routes[j].durations) {
+			return len(routes[i].durations) > len(routes[j].durations)
+		}
+		return routes[i].route < routes[j].route
+	})
+
+	fmt.Fprintln(w, "LATENCY BY ROUTE")
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ROUTE\tCOUNT\tP50\tP90\tP99\tMAX\t2XX\t4XX\t5XX")
+	for _, rs := range routes {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\t%d\t%d\t%d\n",
+			rs.route,
+			len(rs.durations),
+			percentile(rs.durations, 0.50),
+			percentile(rs.durations, 0.90),
+			percentile(rs.durations, 0.99),
+			rs.durations[len(rs.durations)-1],
+			rs.classes[2],
+			rs.classes[4],
+			rs.classes[5],
+		)
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+}
+
+func printStatusCodes(w io.Writer, requests []requestStat) {
+	counts := make(map[int]int)
+	for _, r := range requests {
+		counts[r.statusCode]++
+	}
+
+	codes := make([]int, 0, len(counts))
+	for code := range counts {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+
+	fmt.Fprintln(w, "STATUS CODES")
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tCOUNT")
+	for _, code := range codes {
+		status := strconv.Itoa(code)
+		if code == 0 {
+			status = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%d\n", status, counts[code])
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+}
+
+func printSlowest(w io.Writer, requests []requestStat, top int) {
+	if top <= 0 {
+		return
+	}
+
+	slowest := make([]requestStat, len(requests))
+	copy(slowest, requests)
+	sort.SliceStable(slowest, func(i, j int) bool { return slowest[i].since > slowest[j].since })
+	if len(slowest) > top {
+		slowest = slowest[:top]
+	}
+
+	fmt.Fprintln(w, "SLOWEST TRACES")
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TRACE_ID\tROUTE\tSTATUS\tDURATION")
+	for _, r := range slowest {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", orDash(r.traceID), r.route, r.statusCode, r.since)
+	}
+	tw.Flush()
+}
+
+// percentile retorna o percentil p de durações já ordenadas, pelo método do
+// posto mais próximo
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+
+	return sorted[idx]
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// runTrace implementa o subcomando trace: mostra todas as linhas de um
+// trace_id, incluindo as queries feitas no banco, com o tempo relativo ao
+// início da requisição
+func runTrace(args []string) error {
+	fs := flag.NewFlagSet("trace", flag.ExitOnError)
+	color := fs.String("color", "auto", "colore o nível: auto (apenas em terminal), always ou never")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "uso: logfmt trace [flags] <trace_id> [arquivo]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	traceID := fs.Arg(0)
+	if traceID == "" {
+		fs.Usage()
+		return errors.New("missing trace_id")
+	}
+
+	colored, err := useColor(*color)
+	if err != nil {
+		return err
+	}
+
+	in, err := openInput(fs.Arg(1), false)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	var entries []entry
+	lr := newLineReader(in, false)
+	for {
+		s, err := lr.next(context.Background())
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+
+		if e, ok := parseEntry(s); ok && e.traceID == traceID {
+			entries = append(entries, e)
+		}
+	}
+
+	if len(entries) == 0 {
+		return fmt.Errorf("no entries found for trace %s", traceID)
+	}
+
+	printTrace(os.Stdout, entries, formatter{color: colored})
+
+	return nil
+}
+
+// printTrace mostra as entradas em ordem de tempo. Entradas sem data ficam no
+// fim, na ordem em que foram lidas
+func printTrace(w io.Writer, entries []entry, fmtr formatter) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		ti, tj := entries[i].ts, entries[j].ts
+		if ti.IsZero() || tj.IsZero() {
+			return !ti.IsZero() && tj.IsZero()
+		}
+		return ti.Before(tj)
+	})
+
+	start := entries[0].ts
+
+	fmt.Fprintf(w, "trace %s", entries[0].traceID)
+	if !start.IsZero() {
+		fmt.Fprintf(w, " started at %s", start.Format(time.RFC3339Nano))
+	}
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, e := range entries {
+		offset := "-"
+		if !start.IsZero() && !e.ts.IsZero() {
+			offset = fmt.Sprintf("+%s", e.ts.Sub(start).Round(time.Microsecond))
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", offset, fmtr.level(e.level), orDash(e.caller), e.msg, traceDetails(e))
+	}
+	tw.Flush()
+}
+
+// traceDetails mostra os campos da entrada, com a query e a duração primeiro
+// nos logs de banco
+func traceDetails(e entry) string {
+	var details []string
+	if strings.HasPrefix(e.msg, "database") {
+		for _, k := range []string{"duration", "query"} {
+			if v := field(e.fields, k); v != "" {
+				details = append(details, fmt.Sprintf("%s[%s]", k, v))
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(e.fields))
+	for k := range e.fields {
+		if headerFields[k] || (strings.HasPrefix(e.msg, "database") && (k == "duration" || k == "query")) {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		details = append(details, fmt.Sprintf("%s[%v]", k, e.fields[k]))
+	}
+
+	return strings.Join(details, " ")
+}
@@ -0,0 +1,156 @@
+// Package category fornece a API de negócio para a árvore de categorias de
+// produtos.
+package category
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/data/order"
+	"github.com/vitoraalmeida/service/business/sys/errs"
+	"go.uber.org/zap"
+)
+
+// Conjunto de erros para operações CRUD
+var (
+	ErrNotFound      = errs.New(errs.CategoryNotFound, "category not found")
+	ErrUniqueName    = errs.New(errs.CategoryNameTaken, "name already exists under the parent category")
+	ErrCycle         = errs.New(errs.CategoryCycle, "category cannot be moved under itself")
+	ErrHasChildren   = errs.New(errs.CategoryHasChildren, "category has subcategories")
+	ErrParentMissing = errs.New(errs.CategoryParentNotFound, "parent category not found")
+)
+
+// Storer abstrai qual é a implementação de fato que vai gerenciar a interação
+// com o armazenamento de categorias
+type Storer interface {
+	Create(ctx context.Context, cat Category) error
+	Update(ctx context.Context, cat Category) error
+	Delete(ctx context.Context, cat Category) error
+	Query(ctx context.Context, orderBy order.By, pageNumber int, rowsPerPage int) ([]Category, error)
+	Count(ctx context.Context) (int, error)
+	QueryByID(ctx context.Context, categoryID uuid.UUID) (Category, error)
+	QuerySubtree(ctx context.Context, categoryID uuid.UUID) ([]Category, error)
+}
+
+// Core é a API para o domínio de categorias
+type Core struct {
+	log    *zap.SugaredLogger
+	storer Storer
+}
+
+// NewCore constrói Core para uso da API de categorias
+func NewCore(log *zap.SugaredLogger, storer Storer) *Core {
+	return &Core{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// Create adiciona uma nova categoria, na raiz ou abaixo de ParentID
+func (c *Core) Create(ctx context.Context, nc NewCategory) (Category, error) {
+	if nc.ParentID != nil {
+		if _, err := c.storer.QueryByID(ctx, *nc.ParentID); err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return Category{}, fmt.Errorf("create: parentID[%s]: %w", *nc.ParentID, ErrParentMissing)
+			}
+			return Category{}, fmt.Errorf("create: %w", err)
+		}
+	}
+
+	now := time.Now()
+
+	cat := Category{
+		ID:          uuid.New(),
+		ParentID:    nc.ParentID,
+		Name:        nc.Name,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := c.storer.Create(ctx, cat); err != nil {
+		return Category{}, fmt.Errorf("create: %w", err)
+	}
+
+	return cat, nil
+}
+
+// Update modifica uma categoria. Mover uma categoria para dentro da própria
+// subárvore é rejeitado com ErrCycle. A verificação é feita pelo store, na
+// mesma transação da alteração, para que mudanças concorrentes não formem um
+// ciclo
+func (c *Core) Update(ctx context.Context, cat Category, uc UpdateCategory) (Category, error) {
+	if uc.Name != nil {
+		cat.Name = *uc.Name
+	}
+
+	switch {
+	case uc.MoveToRoot:
+		cat.ParentID = nil
+
+	case uc.ParentID != nil:
+		if _, err := c.storer.QueryByID(ctx, *uc.ParentID); err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return Category{}, fmt.Errorf("update: parentID[%s]: %w", *uc.ParentID, ErrParentMissing)
+			}
+			return Category{}, fmt.Errorf("update: %w", err)
+		}
+
+		cat.ParentID = uc.ParentID
+	}
+
+	cat.DateUpdated = time.Now()
+
+	if err := c.storer.Update(ctx, cat); err != nil {
+		return Category{}, fmt.Errorf("update: %w", err)
+	}
+
+	return cat, nil
+}
+
+// Delete remove uma categoria sem subcategorias. Produtos da categoria ficam
+// sem categoria
+func (c *Core) Delete(ctx context.Context, cat Category) error {
+	if err := c.storer.Delete(ctx, cat); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// Query busca as categorias com paginação
+func (c *Core) Query(ctx context.Context, orderBy order.By, pageNumber int, rowsPerPage int) ([]Category, error) {
+	cats, err := c.storer.Query(ctx, orderBy, pageNumber, rowsPerPage)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return cats, nil
+}
+
+// Count retorna o número total de categorias
+func (c *Core) Count(ctx context.Context) (int, error) {
+	return c.storer.Count(ctx)
+}
+
+// QueryByID busca a categoria especificada
+func (c *Core) QueryByID(ctx context.Context, categoryID uuid.UUID) (Category, error) {
+	cat, err := c.storer.QueryByID(ctx, categoryID)
+	if err != nil {
+		return Category{}, fmt.Errorf("query: categoryID[%s]: %w", categoryID, err)
+	}
+
+	return cat, nil
+}
+
+// QuerySubtree busca a categoria especificada e todas as suas descendentes
+func (c *Core) QuerySubtree(ctx context.Context, categoryID uuid.UUID) ([]Category, error) {
+	cats, err := c.storer.QuerySubtree(ctx, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("querysubtree: categoryID[%s]: %w", categoryID, err)
+	}
+
+	return cats, nil
+}
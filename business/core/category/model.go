@@ -0,0 +1,32 @@
+package category
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Category representa uma categoria de produtos. Categorias formam uma árvore
+// por meio de ParentID
+type Category struct {
+	ID          uuid.UUID
+	ParentID    *uuid.UUID // nulo para categorias na raiz
+	Name        string
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// NewCategory contém informação necessária para criar uma categoria
+type NewCategory struct {
+	Name     string
+	ParentID *uuid.UUID
+}
+
+// UpdateCategory contém informação necessária para atualizar uma categoria.
+// Campos nulos não são alterados. Para mover a categoria para a raiz use
+// MoveToRoot
+type UpdateCategory struct {
+	Name       *string
+	ParentID   *uuid.UUID
+	MoveToRoot bool
+}
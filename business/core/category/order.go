@@ -0,0 +1,12 @@
+package category
+
+import "github.com/vitoraalmeida/service/business/data/order"
+
+// DefaultOrderBy representa a forma padrão de ordenação
+var DefaultOrderBy = order.NewBy(OrderByName, order.ASC)
+
+// Conjunto de campos que podem ser usado para ordenar os resultados
+const (
+	OrderByID   = "categoryid"
+	OrderByName = "name"
+)
@@ -0,0 +1,270 @@
+// Package categorydb contém as funcionalidades CRUD relacionadas a categorias.
+package categorydb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/vitoraalmeida/service/business/core/category"
+	"github.com/vitoraalmeida/service/business/data/order"
+	database "github.com/vitoraalmeida/service/business/sys/database/pgx"
+	"go.uber.org/zap"
+)
+
+// Store gerencia o conjunto de API que usamos para interagir com o banco de dados
+type Store struct {
+	log *zap.SugaredLogger
+	db  *sqlx.DB
+}
+
+// NewStore constrói a API de acesso aos dados de categorias
+func NewStore(log *zap.SugaredLogger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Create insere uma nova categoria no banco
+func (s *Store) Create(ctx context.Context, cat category.Category) error {
+	const q = `
+	INSERT INTO categories
+		(category_id, parent_id, name, date_created, date_updated)
+	VALUES
+		(:category_id, :parent_id, :name, :date_created, :date_updated)`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, toDBCategory(cat)); err != nil {
+		if errors.Is(err, database.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", category.ErrUniqueName)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update substitui a categoria no banco de dados. Quando a categoria tem um
+// pai, a verificação de ciclo e a alteração são feitas na mesma transação,
+// com a tabela bloqueada para outras escritas. Assim duas mudanças de pai
+// concorrentes, como A para baixo de B e B para baixo de A, não podem passar
+// as duas pela verificação e formar um ciclo
+func (s *Store) Update(ctx context.Context, cat category.Category) error {
+	const q = `
+	UPDATE
+		categories
+	SET
+		"parent_id" = :parent_id,
+		"name" = :name,
+		"date_updated" = :date_updated
+	WHERE
+		category_id = :category_id`
+
+	// SHARE ROW EXCLUSIVE conflita consigo mesmo, então as alterações de
+	// categorias são serializadas, mas as leituras continuam livres
+	const qLock = `LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE`
+
+	// a categoria não pode estar entre os ancestrais do novo pai, incluindo
+	// o próprio pai
+	const qCycle = `
+	WITH RECURSIVE ancestors AS (
+		SELECT
+			category_id, parent_id
+		FROM
+			categories
+		WHERE
+			category_id = :parent_id
+		UNION
+		SELECT
+			c.category_id, c.parent_id
+		FROM
+			categories AS c
+		JOIN
+			ancestors AS a ON c.category_id = a.parent_id
+	)
+	SELECT
+		count(1)
+	FROM
+		ancestors
+	WHERE
+		category_id = :category_id`
+
+	dbCat := toDBCategory(cat)
+
+	f := func(tx *sqlx.Tx) error {
+		if dbCat.ParentID.Valid {
+			if err := database.ExecContext(ctx, s.log, tx, qLock); err != nil {
+				return fmt.Errorf("execcontext: %w", err)
+			}
+
+			var count struct {
+				Count int `db:"count"`
+			}
+			if err := database.NamedQueryStruct(ctx, s.log, tx, qCycle, dbCat, &count); err != nil {
+				return fmt.Errorf("namedquerystruct: %w", err)
+			}
+			if count.Count > 0 {
+				return category.ErrCycle
+			}
+		}
+
+		if err := database.NamedExecContext(ctx, s.log, tx, q, dbCat); err != nil {
+			switch {
+			case errors.Is(err, database.ErrDBDuplicatedEntry):
+				return fmt.Errorf("namedexeccontext: %w", category.ErrUniqueName)
+			case errors.Is(err, database.ErrDBForeignKey):
+				return fmt.Errorf("namedexeccontext: %w", category.ErrParentMissing)
+			}
+			return fmt.Errorf("namedexeccontext: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := database.WithinTran(ctx, s.log, s.db, f); err != nil {
+		return fmt.Errorf("withintran: %w", err)
+	}
+
+	return nil
+}
+
+// Delete remove uma categoria do banco de dados
+func (s *Store) Delete(ctx context.Context, cat category.Category) error {
+	data := struct {
+		ID string `db:"category_id"`
+	}{
+		ID: cat.ID.String(),
+	}
+
+	const q = `
+	DELETE FROM
+		categories
+	WHERE
+		category_id = :category_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		if errors.Is(err, database.ErrDBForeignKey) {
+			return fmt.Errorf("namedexeccontext: %w", category.ErrHasChildren)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Query busca uma lista de categorias existentes no banco
+func (s *Store) Query(ctx context.Context, orderBy order.By, pageNumber int, rowsPerPage int) ([]category.Category, error) {
+	data := map[string]interface{}{
+		"offset":        (pageNumber - 1) * rowsPerPage,
+		"rows_per_page": rowsPerPage,
+	}
+
+	const q = `
+	SELECT
+		category_id, parent_id, name, date_created, date_updated
+	FROM
+		categories`
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBufferString(q)
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbCats []dbCategory
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbCats); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreCategorySlice(dbCats), nil
+}
+
+// Count retorna o total de categorias no banco
+func (s *Store) Count(ctx context.Context) (int, error) {
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		categories`
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := database.QueryStruct(ctx, s.log, s.db, q, &count); err != nil {
+		return 0, fmt.Errorf("querystruct: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryByID busca a categoria especificada
+func (s *Store) QueryByID(ctx context.Context, categoryID uuid.UUID) (category.Category, error) {
+	data := struct {
+		ID string `db:"category_id"`
+	}{
+		ID: categoryID.String(),
+	}
+
+	const q = `
+	SELECT
+		category_id, parent_id, name, date_created, date_updated
+	FROM
+		categories
+	WHERE
+		category_id = :category_id`
+
+	var dbCat dbCategory
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbCat); err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return category.Category{}, fmt.Errorf("namedquerystruct: %w", category.ErrNotFound)
+		}
+		return category.Category{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreCategory(dbCat), nil
+}
+
+// QuerySubtree busca a categoria especificada e todas as suas descendentes.
+// UNION descarta as linhas repetidas, então a consulta termina mesmo que a
+// árvore tenha um ciclo
+func (s *Store) QuerySubtree(ctx context.Context, categoryID uuid.UUID) ([]category.Category, error) {
+	data := struct {
+		ID string `db:"category_id"`
+	}{
+		ID: categoryID.String(),
+	}
+
+	const q = `
+	WITH RECURSIVE subtree AS (
+		SELECT
+			category_id, parent_id, name, date_created, date_updated
+		FROM
+			categories
+		WHERE
+			category_id = :category_id
+		UNION
+		SELECT
+			c.category_id, c.parent_id, c.name, c.date_created, c.date_updated
+		FROM
+			categories AS c
+		JOIN
+			subtree AS s ON c.parent_id = s.category_id
+	)
+	SELECT
+		category_id, parent_id, name, date_created, date_updated
+	FROM
+		subtree`
+
+	var dbCats []dbCategory
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbCats); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreCategorySlice(dbCats), nil
+}
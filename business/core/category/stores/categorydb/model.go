@@ -0,0 +1,60 @@
+package categorydb
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/core/category"
+)
+
+// dbCategory representa a estrutura que precisamos para mover dados entre a
+// aplicação e a tabela categories
+type dbCategory struct {
+	ID          uuid.UUID     `db:"category_id"`
+	ParentID    uuid.NullUUID `db:"parent_id"`
+	Name        string        `db:"name"`
+	DateCreated time.Time     `db:"date_created"`
+	DateUpdated time.Time     `db:"date_updated"`
+}
+
+// converte uma Category de domínio em dbCategory para inserir dados no banco
+func toDBCategory(cat category.Category) dbCategory {
+	var parentID uuid.NullUUID
+	if cat.ParentID != nil {
+		parentID = uuid.NullUUID{UUID: *cat.ParentID, Valid: true}
+	}
+
+	return dbCategory{
+		ID:          cat.ID,
+		ParentID:    parentID,
+		Name:        cat.Name,
+		DateCreated: cat.DateCreated.UTC(),
+		DateUpdated: cat.DateUpdated.UTC(),
+	}
+}
+
+// converte de dbCategory para Category de domínio
+func toCoreCategory(dbCat dbCategory) category.Category {
+	var parentID *uuid.UUID
+	if dbCat.ParentID.Valid {
+		id := dbCat.ParentID.UUID
+		parentID = &id
+	}
+
+	return category.Category{
+		ID:          dbCat.ID,
+		ParentID:    parentID,
+		Name:        dbCat.Name,
+		DateCreated: dbCat.DateCreated.In(time.Local),
+		DateUpdated: dbCat.DateUpdated.In(time.Local),
+	}
+}
+
+// converte o slice de dbCategory em slice de domínio
+func toCoreCategorySlice(dbCats []dbCategory) []category.Category {
+	cats := make([]category.Category, len(dbCats))
+	for i, dbCat := range dbCats {
+		cats[i] = toCoreCategory(dbCat)
+	}
+	return cats
+}
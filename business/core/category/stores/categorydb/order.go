@@ -0,0 +1,38 @@
+package categorydb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vitoraalmeida/service/business/core/category"
+	"github.com/vitoraalmeida/service/business/data/order"
+)
+
+var orderByFields = map[string]string{
+	category.OrderByID:   "category_id",
+	category.OrderByName: "name",
+}
+
+// adiciona na query que vai ser executada a parte da ordenação, terminando
+// sempre na chave primária para que a ordem das linhas seja determinística
+func orderByClause(orderBy order.By) (string, error) {
+	parts := make([]string, 0, len(orderBy.Fields)+1)
+
+	for _, fld := range orderBy.Fields {
+		by, exists := orderByFields[fld.Name]
+		if !exists {
+			return "", fmt.Errorf("field %q does not exist", fld.Name)
+		}
+
+		parts = append(parts, by+" "+fld.Direction)
+
+		// nenhum campo depois da chave primária altera a ordem
+		if fld.Name == category.OrderByID {
+			return " ORDER BY " + strings.Join(parts, ", "), nil
+		}
+	}
+
+	parts = append(parts, "category_id "+order.ASC)
+
+	return " ORDER BY " + strings.Join(parts, ", "), nil
+}
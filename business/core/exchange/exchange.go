@@ -0,0 +1,197 @@
+// Package exchange fornece a API de negócio para as taxas de câmbio usadas na
+// conversão de valores entre moedas. Todas as taxas são relativas à moeda
+// padrão (money.DefaultCurrency), que tem sempre taxa 1.
+package exchange
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/vitoraalmeida/service/business/data/money"
+	"github.com/vitoraalmeida/service/business/sys/errs"
+	"github.com/vitoraalmeida/service/business/sys/validate"
+	"go.uber.org/zap"
+)
+
+// Conjunto de erros para operações com taxas de câmbio
+var (
+	ErrNotFound     = errs.New(errs.ExchangeRateNotFound, "exchange rate not found")
+	ErrBaseCurrency = errs.New(errs.BaseCurrencyRate, "default currency rate cannot be changed")
+	ErrInUse        = errs.New(errs.CurrencyInUse, "currency is in use by products")
+)
+
+// Storer abstrai qual é a implementação de fato que vai gerenciar a interação
+// com o armazenamento das taxas de câmbio
+type Storer interface {
+	Upsert(ctx context.Context, rates []Rate) error
+	Delete(ctx context.Context, currency string) error
+	Query(ctx context.Context) ([]Rate, error)
+	QueryByCurrency(ctx context.Context, currency string) (Rate, error)
+}
+
+// Core é a API para o domínio de câmbio
+type Core struct {
+	log    *zap.SugaredLogger
+	storer Storer
+}
+
+// NewCore constrói Core para uso da API de câmbio
+func NewCore(log *zap.SugaredLogger, storer Storer) *Core {
+	return &Core{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// Set cria ou atualiza a taxa de câmbio de uma moeda
+func (c *Core) Set(ctx context.Context, currency string, rate money.Rate) (Rate, error) {
+	rt, err := newRate(currency, rate, time.Now())
+	if err != nil {
+		return Rate{}, fmt.Errorf("set: %w", err)
+	}
+
+	if err := c.storer.Upsert(ctx, []Rate{rt}); err != nil {
+		return Rate{}, fmt.Errorf("set: %w", err)
+	}
+
+	return rt, nil
+}
+
+// Import lê taxas de câmbio no formato CSV "currency,rate" e grava todas de
+// uma vez. A primeira linha pode ser um cabeçalho. Se alguma linha for
+// inválida nada é gravado e os erros são retornados por linha
+func (c *Core) Import(ctx context.Context, r io.Reader) ([]Rate, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 2
+	cr.TrimLeadingSpace = true
+
+	now := time.Now()
+	var rates []Rate
+	var fieldErrs validate.FieldErrors
+
+	for line := 1; ; line++ {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("import: line[%d]: %w", line, err)
+		}
+
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// apenas erros de formato são da linha. Uma falha ao ler o corpo se
+			// repete em toda chamada e interrompe a importação
+			var pe *csv.ParseError
+			if !errors.As(err, &pe) {
+				return nil, fmt.Errorf("import: line[%d]: %w", line, err)
+			}
+			fieldErrs = append(fieldErrs, validate.FieldError{Field: fmt.Sprintf("line[%d]", line), Err: pe.Err.Error()})
+			continue
+		}
+
+		if line == 1 && strings.EqualFold(record[0], "currency") {
+			continue
+		}
+
+		rate, err := money.ParseRate(record[1])
+		if err != nil {
+			fieldErrs = append(fieldErrs, validate.FieldError{Field: fmt.Sprintf("line[%d]", line), Err: err.Error()})
+			continue
+		}
+
+		rt, err := newRate(strings.ToUpper(record[0]), rate, now)
+		if err != nil {
+			fieldErrs = append(fieldErrs, validate.FieldError{Field: fmt.Sprintf("line[%d]", line), Err: err.Error()})
+			continue
+		}
+
+		rates = append(rates, rt)
+	}
+
+	if len(fieldErrs) > 0 {
+		return nil, fieldErrs
+	}
+
+	if err := c.storer.Upsert(ctx, rates); err != nil {
+		return nil, fmt.Errorf("import: %w", err)
+	}
+
+	return rates, nil
+}
+
+// Delete remove a taxa de câmbio de uma moeda que não esteja em uso
+func (c *Core) Delete(ctx context.Context, currency string) error {
+	if currency == money.DefaultCurrency {
+		return fmt.Errorf("delete: %w", ErrBaseCurrency)
+	}
+
+	if err := c.storer.Delete(ctx, currency); err != nil {
+		return fmt.Errorf("delete: currency[%s]: %w", currency, err)
+	}
+
+	return nil
+}
+
+// Query retorna todas as taxas de câmbio cadastradas
+func (c *Core) Query(ctx context.Context) ([]Rate, error) {
+	rates, err := c.storer.Query(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return rates, nil
+}
+
+// QueryByCurrency retorna a taxa de câmbio de uma moeda
+func (c *Core) QueryByCurrency(ctx context.Context, currency string) (Rate, error) {
+	rate, err := c.storer.QueryByCurrency(ctx, currency)
+	if err != nil {
+		return Rate{}, fmt.Errorf("query: currency[%s]: %w", currency, err)
+	}
+
+	return rate, nil
+}
+
+// Rates retorna as taxas de câmbio agrupadas por moeda para conversão
+func (c *Core) Rates(ctx context.Context) (Rates, error) {
+	rates, err := c.storer.Query(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("rates: %w", err)
+	}
+
+	rs := make(Rates, len(rates))
+	for _, rt := range rates {
+		rs[rt.Currency] = rt.Rate
+	}
+
+	return rs, nil
+}
+
+// =============================================================================
+
+// newRate valida e constrói uma taxa de câmbio. A moeda padrão tem sempre
+// taxa 1
+func newRate(currency string, rate money.Rate, now time.Time) (Rate, error) {
+	if err := money.ValidateCurrency(currency); err != nil {
+		return Rate{}, err
+	}
+
+	if rate.IsZero() {
+		return Rate{}, money.ErrInvalidRate
+	}
+
+	if currency == money.DefaultCurrency && rate != money.MustParseRate("1") {
+		return Rate{}, ErrBaseCurrency
+	}
+
+	return Rate{
+		Currency:    currency,
+		Rate:        rate,
+		DateUpdated: now,
+	}, nil
+}
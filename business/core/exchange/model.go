@@ -0,0 +1,38 @@
+package exchange
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vitoraalmeida/service/business/data/money"
+)
+
+// Rate representa a taxa de câmbio de uma moeda em relação à moeda padrão
+type Rate struct {
+	Currency    string
+	Rate        money.Rate
+	DateUpdated time.Time
+}
+
+// Rates agrupa as taxas de câmbio por moeda para converter vários valores com
+// uma única consulta ao banco
+type Rates map[string]money.Rate
+
+// Convert converte o valor para a moeda informada
+func (r Rates) Convert(m money.Money, currency string) (money.Money, error) {
+	if m.Currency() == currency {
+		return m, nil
+	}
+
+	from, exists := r[m.Currency()]
+	if !exists {
+		return money.Money{}, fmt.Errorf("currency[%s]: %w", m.Currency(), ErrNotFound)
+	}
+
+	to, exists := r[currency]
+	if !exists {
+		return money.Money{}, fmt.Errorf("currency[%s]: %w", currency, ErrNotFound)
+	}
+
+	return money.Convert(m, from, to, currency)
+}
@@ -0,0 +1,125 @@
+// Package exchangedb contém as funcionalidades de acesso ao banco relacionadas
+// às taxas de câmbio.
+package exchangedb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vitoraalmeida/service/business/core/exchange"
+	database "github.com/vitoraalmeida/service/business/sys/database/pgx"
+	"go.uber.org/zap"
+)
+
+// Store gerencia o conjunto de API que usamos para interagir com o banco de dados
+type Store struct {
+	log *zap.SugaredLogger
+	db  *sqlx.DB
+}
+
+// NewStore constrói a API de acesso às taxas de câmbio
+func NewStore(log *zap.SugaredLogger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Upsert cria ou atualiza as taxas informadas numa única transação
+func (s *Store) Upsert(ctx context.Context, rates []exchange.Rate) error {
+	const q = `
+	INSERT INTO exchange_rates
+		(currency, rate, date_updated)
+	VALUES
+		(:currency, :rate, :date_updated)
+	ON CONFLICT (currency) DO UPDATE SET
+		rate = EXCLUDED.rate,
+		date_updated = EXCLUDED.date_updated`
+
+	f := func(tx *sqlx.Tx) error {
+		for _, rt := range rates {
+			if err := database.NamedExecContext(ctx, s.log, tx, q, toDBRate(rt)); err != nil {
+				return fmt.Errorf("namedexeccontext: %w", err)
+			}
+		}
+		return nil
+	}
+
+	if err := database.WithinTran(ctx, s.log, s.db, f); err != nil {
+		return fmt.Errorf("withintran: %w", err)
+	}
+
+	return nil
+}
+
+// Delete remove a taxa de uma moeda. Moedas usadas por produtos não podem ser
+// removidas
+func (s *Store) Delete(ctx context.Context, currency string) error {
+	data := struct {
+		Currency string `db:"currency"`
+	}{
+		Currency: currency,
+	}
+
+	const q = `
+	DELETE FROM
+		exchange_rates
+	WHERE
+		currency = :currency`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		if errors.Is(err, database.ErrDBForeignKey) {
+			return exchange.ErrInUse
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Query busca todas as taxas de câmbio
+func (s *Store) Query(ctx context.Context) ([]exchange.Rate, error) {
+	const q = `
+	SELECT
+		currency, rate, date_updated
+	FROM
+		exchange_rates
+	ORDER BY
+		currency`
+
+	var dbRts []dbRate
+	if err := database.QuerySlice(ctx, s.log, s.db, q, &dbRts); err != nil {
+		return nil, fmt.Errorf("queryslice: %w", err)
+	}
+
+	return toCoreRateSlice(dbRts), nil
+}
+
+// QueryByCurrency busca a taxa de câmbio de uma moeda
+func (s *Store) QueryByCurrency(ctx context.Context, currency string) (exchange.Rate, error) {
+	data := struct {
+		Currency string `db:"currency"`
+	}{
+		Currency: currency,
+	}
+
+	const q = `
+	SELECT
+		currency, rate, date_updated
+	FROM
+		exchange_rates
+	WHERE
+		currency = :currency`
+
+	var dbRt dbRate
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbRt); err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return exchange.Rate{}, fmt.Errorf("namedquerystruct: %w", exchange.ErrNotFound)
+		}
+		return exchange.Rate{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreRate(dbRt), nil
+}
@@ -0,0 +1,42 @@
+package exchangedb
+
+import (
+	"time"
+
+	"github.com/vitoraalmeida/service/business/core/exchange"
+	"github.com/vitoraalmeida/service/business/data/money"
+)
+
+// dbRate representa uma linha da tabela exchange_rates
+type dbRate struct {
+	Currency    string     `db:"currency"`
+	Rate        money.Rate `db:"rate"`
+	DateUpdated time.Time  `db:"date_updated"`
+}
+
+// converte uma Rate de domínio em dbRate para inserir dados no banco
+func toDBRate(rt exchange.Rate) dbRate {
+	return dbRate{
+		Currency:    rt.Currency,
+		Rate:        rt.Rate,
+		DateUpdated: rt.DateUpdated.UTC(),
+	}
+}
+
+// converte de dbRate para Rate de domínio
+func toCoreRate(dbRt dbRate) exchange.Rate {
+	return exchange.Rate{
+		Currency:    dbRt.Currency,
+		Rate:        dbRt.Rate,
+		DateUpdated: dbRt.DateUpdated.In(time.Local),
+	}
+}
+
+// converte o slice de dbRate em slice de domínio
+func toCoreRateSlice(dbRts []dbRate) []exchange.Rate {
+	rts := make([]exchange.Rate, len(dbRts))
+	for i, dbRt := range dbRts {
+		rts[i] = toCoreRate(dbRt)
+	}
+	return rts
+}
@@ -0,0 +1,157 @@
+// Package inventory fornece a API de negócio para o histórico de movimentações
+// de estoque. Toda alteração na quantidade de um produto deve passar por aqui
+// para que o motivo e o responsável fiquem registrados.
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/core/product"
+	"github.com/vitoraalmeida/service/business/sys/errs"
+	"go.uber.org/zap"
+)
+
+// Conjunto de erros para operações de estoque
+var (
+	ErrInsufficientStock = errs.New(errs.InsufficientStock, "insufficient stock for product")
+	ErrInvalidQuantity   = errs.New(errs.InvalidQuantity, "invalid quantity for movement kind")
+)
+
+// Storer abstrai qual é a implementação de fato que vai gerenciar a interação
+// com o armazenamento das movimentações
+type Storer interface {
+	// Record deve registrar a movimentação e aplicar a variação na quantidade
+	// do produto de forma atômica, retornando ErrInsufficientStock se o
+	// estoque ficaria negativo
+	Record(ctx context.Context, mv Movement) error
+	QueryByProductID(ctx context.Context, productID uuid.UUID, pageNumber int, rowsPerPage int) ([]Movement, error)
+	CountByProductID(ctx context.Context, productID uuid.UUID) (int, error)
+	QueryDrift(ctx context.Context) ([]Drift, error)
+	Reconcile(ctx context.Context, drifts []Drift) error
+}
+
+// Core é a API para o domínio de estoque
+type Core struct {
+	log     *zap.SugaredLogger
+	prdCore *product.Core
+	storer  Storer
+}
+
+// NewCore constrói Core para uso da API de estoque
+func NewCore(log *zap.SugaredLogger, prdCore *product.Core, storer Storer) *Core {
+	return &Core{
+		log:     log,
+		prdCore: prdCore,
+		storer:  storer,
+	}
+}
+
+// Record registra uma movimentação de estoque para um produto existente e
+// atualiza sua quantidade
+func (c *Core) Record(ctx context.Context, nm NewMovement) (Movement, error) {
+	delta, err := delta(nm.Kind, nm.Quantity)
+	if err != nil {
+		return Movement{}, fmt.Errorf("record: %w", err)
+	}
+
+	if _, err := c.prdCore.QueryByID(ctx, nm.ProductID); err != nil {
+		return Movement{}, fmt.Errorf("record: %w", err)
+	}
+
+	mv := Movement{
+		ID:          uuid.New(),
+		ProductID:   nm.ProductID,
+		UserID:      nm.UserID,
+		Kind:        nm.Kind,
+		Quantity:    delta,
+		Reason:      nm.Reason,
+		DateCreated: time.Now(),
+	}
+
+	if err := c.storer.Record(ctx, mv); err != nil {
+		return Movement{}, fmt.Errorf("record: %w", err)
+	}
+
+	return mv, nil
+}
+
+// QueryByProductID busca o histórico de movimentações de um produto, das mais
+// recentes para as mais antigas
+func (c *Core) QueryByProductID(ctx context.Context, productID uuid.UUID, pageNumber int, rowsPerPage int) ([]Movement, error) {
+	mvs, err := c.storer.QueryByProductID(ctx, productID, pageNumber, rowsPerPage)
+	if err != nil {
+		return nil, fmt.Errorf("query: productID[%s]: %w", productID, err)
+	}
+
+	return mvs, nil
+}
+
+// CountByProductID retorna o número de movimentações de um produto
+func (c *Core) CountByProductID(ctx context.Context, productID uuid.UUID) (int, error) {
+	return c.storer.CountByProductID(ctx, productID)
+}
+
+// Drift retorna os produtos cuja quantidade não confere com o histórico
+func (c *Core) Drift(ctx context.Context) ([]Drift, error) {
+	drifts, err := c.storer.QueryDrift(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("drift: %w", err)
+	}
+
+	return drifts, nil
+}
+
+// Reconcile corrige a quantidade dos produtos divergentes para o valor
+// calculado a partir do histórico, que é considerado a fonte da verdade.
+// Retorna os produtos que foram corrigidos
+func (c *Core) Reconcile(ctx context.Context) ([]Drift, error) {
+	drifts, err := c.storer.QueryDrift(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reconcile: %w", err)
+	}
+
+	if len(drifts) == 0 {
+		return nil, nil
+	}
+
+	if err := c.storer.Reconcile(ctx, drifts); err != nil {
+		return nil, fmt.Errorf("reconcile: %w", err)
+	}
+
+	for _, d := range drifts {
+		c.log.Infow("reconcile", "productID", d.ProductID, "quantity", d.Quantity, "ledger", d.LedgerQuantity)
+	}
+
+	return drifts, nil
+}
+
+// =============================================================================
+
+// delta converte a quantidade informada na variação de estoque de acordo com o
+// tipo da movimentação
+func delta(kind Kind, quantity int) (int, error) {
+	switch kind {
+	case KindRestock, KindReturn:
+		if quantity <= 0 {
+			return 0, ErrInvalidQuantity
+		}
+		return quantity, nil
+
+	case KindSale:
+		if quantity <= 0 {
+			return 0, ErrInvalidQuantity
+		}
+		return -quantity, nil
+
+	case KindAdjustment:
+		if quantity == 0 {
+			return 0, ErrInvalidQuantity
+		}
+		return quantity, nil
+	}
+
+	return 0, fmt.Errorf("unknown kind %q", kind.Name())
+}
@@ -0,0 +1,70 @@
+package inventory
+
+import "errors"
+
+// Tipos possíveis de movimentação de estoque
+var (
+	KindRestock    = Kind{"RESTOCK"}    // entrada de mercadoria
+	KindSale       = Kind{"SALE"}       // saída por venda
+	KindAdjustment = Kind{"ADJUSTMENT"} // correção manual, pode ser positiva ou negativa
+	KindReturn     = Kind{"RETURN"}     // devolução de um cliente
+)
+
+// Conjunto dos tipos de movimentação
+var kinds = map[string]Kind{
+	KindRestock.name:    KindRestock,
+	KindSale.name:       KindSale,
+	KindAdjustment.name: KindAdjustment,
+	KindReturn.name:     KindReturn,
+}
+
+// Kind representa o tipo de uma movimentação de estoque
+type Kind struct {
+	name string
+}
+
+// ParseKind recebe um texto e converte para um tipo de movimentação existente
+func ParseKind(value string) (Kind, error) {
+	kind, exists := kinds[value]
+	if !exists {
+		return Kind{}, errors.New("invalid kind")
+	}
+
+	return kind, nil
+}
+
+// MustParseKind chama panic() caso ParseKind retorne erro
+func MustParseKind(value string) Kind {
+	kind, err := ParseKind(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return kind
+}
+
+// Name retorna o nome do tipo de movimentação
+func (k Kind) Name() string {
+	return k.name
+}
+
+// UnmarshalText converte json para Kind, rejeitando tipos desconhecidos
+func (k *Kind) UnmarshalText(data []byte) error {
+	kind, err := ParseKind(string(data))
+	if err != nil {
+		return err
+	}
+
+	k.name = kind.name
+	return nil
+}
+
+// MarshalText converte Kind para json
+func (k Kind) MarshalText() ([]byte, error) {
+	return []byte(k.name), nil
+}
+
+// Equal provê suporte para o pacote go-cmp e testing
+func (k Kind) Equal(k2 Kind) bool {
+	return k.name == k2.name
+}
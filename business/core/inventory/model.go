@@ -0,0 +1,39 @@
+package inventory
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Movement representa uma entrada no histórico de estoque de um produto.
+// Quantity é a variação no estoque: positiva para entradas e negativa para
+// saídas
+type Movement struct {
+	ID          uuid.UUID
+	ProductID   uuid.UUID
+	UserID      uuid.UUID // quem realizou a movimentação
+	Kind        Kind
+	Quantity    int
+	Reason      string
+	DateCreated time.Time
+}
+
+// NewMovement contém informação necessária para registrar uma movimentação.
+// Quantity é sempre positiva para RESTOCK, SALE e RETURN, o sentido é dado pelo
+// tipo. Para ADJUSTMENT o sinal indica se é uma entrada ou saída
+type NewMovement struct {
+	ProductID uuid.UUID
+	UserID    uuid.UUID
+	Kind      Kind
+	Quantity  int
+	Reason    string
+}
+
+// Drift representa um produto cuja quantidade em estoque não confere com a
+// soma das movimentações registradas
+type Drift struct {
+	ProductID      uuid.UUID
+	Quantity       int // quantidade armazenada no produto
+	LedgerQuantity int // quantidade calculada a partir do histórico
+}
@@ -0,0 +1,191 @@
+// Package inventorydb contém as funcionalidades de acesso ao banco relacionadas
+// ao histórico de movimentações de estoque.
+package inventorydb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/vitoraalmeida/service/business/core/inventory"
+	database "github.com/vitoraalmeida/service/business/sys/database/pgx"
+	"go.uber.org/zap"
+)
+
+// Store gerencia o conjunto de API que usamos para interagir com o banco de dados
+type Store struct {
+	log *zap.SugaredLogger
+	db  *sqlx.DB
+}
+
+// NewStore constrói a API de acesso ao histórico de estoque
+func NewStore(log *zap.SugaredLogger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Record aplica a variação na quantidade do produto e registra a movimentação
+// numa única transação
+func (s *Store) Record(ctx context.Context, mv inventory.Movement) error {
+	// a condição sobre quantity garante que o estoque nunca fica negativo
+	const qStock = `
+	UPDATE
+		products
+	SET
+		quantity = quantity + CAST(:quantity AS INT),
+		date_updated = :date_created
+	WHERE
+		product_id = :product_id AND quantity + CAST(:quantity AS INT) >= 0
+	RETURNING
+		product_id`
+
+	const qMovement = `
+	INSERT INTO inventory_movements
+		(movement_id, product_id, user_id, kind, quantity, reason, date_created)
+	VALUES
+		(:movement_id, :product_id, :user_id, :kind, :quantity, :reason, :date_created)`
+
+	dbMv := toDBMovement(mv)
+
+	f := func(tx *sqlx.Tx) error {
+		var updated struct {
+			ProductID uuid.UUID `db:"product_id"`
+		}
+		if err := database.NamedQueryStruct(ctx, s.log, tx, qStock, dbMv, &updated); err != nil {
+			if errors.Is(err, database.ErrDBNotFound) {
+				return fmt.Errorf("productID[%s]: %w", mv.ProductID, inventory.ErrInsufficientStock)
+			}
+			return fmt.Errorf("namedquerystruct: %w", err)
+		}
+
+		if err := database.NamedExecContext(ctx, s.log, tx, qMovement, dbMv); err != nil {
+			return fmt.Errorf("namedexeccontext: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := database.WithinTran(ctx, s.log, s.db, f); err != nil {
+		return fmt.Errorf("withintran: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByProductID busca as movimentações de um produto, das mais recentes
+// para as mais antigas
+func (s *Store) QueryByProductID(ctx context.Context, productID uuid.UUID, pageNumber int, rowsPerPage int) ([]inventory.Movement, error) {
+	data := map[string]interface{}{
+		"product_id":    productID,
+		"offset":        (pageNumber - 1) * rowsPerPage,
+		"rows_per_page": rowsPerPage,
+	}
+
+	const q = `
+	SELECT
+		movement_id, product_id, user_id, kind, quantity, reason, date_created
+	FROM
+		inventory_movements
+	WHERE
+		product_id = :product_id
+	ORDER BY
+		date_created DESC, movement_id ASC
+	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
+
+	var dbMvs []dbMovement
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbMvs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreMovementSlice(dbMvs), nil
+}
+
+// CountByProductID retorna o número de movimentações de um produto
+func (s *Store) CountByProductID(ctx context.Context, productID uuid.UUID) (int, error) {
+	data := struct {
+		ID string `db:"product_id"`
+	}{
+		ID: productID.String(),
+	}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		inventory_movements
+	WHERE
+		product_id = :product_id`
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryDrift busca os produtos cuja quantidade difere da soma das
+// movimentações registradas
+func (s *Store) QueryDrift(ctx context.Context) ([]inventory.Drift, error) {
+	const q = `
+	SELECT
+		p.product_id,
+		p.quantity,
+		COALESCE(SUM(m.quantity), 0) AS ledger_quantity
+	FROM
+		products AS p
+	LEFT JOIN
+		inventory_movements AS m ON m.product_id = p.product_id
+	GROUP BY
+		p.product_id
+	HAVING
+		p.quantity <> COALESCE(SUM(m.quantity), 0)
+	ORDER BY
+		p.product_id`
+
+	var dbDfs []dbDrift
+	if err := database.QuerySlice(ctx, s.log, s.db, q, &dbDfs); err != nil {
+		return nil, fmt.Errorf("queryslice: %w", err)
+	}
+
+	return toCoreDriftSlice(dbDfs), nil
+}
+
+// Reconcile substitui a quantidade dos produtos pelo valor do histórico. Um
+// produto só é alterado se a quantidade ainda for a mesma observada na
+// consulta de divergências
+func (s *Store) Reconcile(ctx context.Context, drifts []inventory.Drift) error {
+	const q = `
+	UPDATE
+		products
+	SET
+		quantity = :ledger_quantity
+	WHERE
+		product_id = :product_id AND quantity = :quantity`
+
+	f := func(tx *sqlx.Tx) error {
+		for _, d := range drifts {
+			dbDf := dbDrift{
+				ProductID:      d.ProductID,
+				Quantity:       d.Quantity,
+				LedgerQuantity: d.LedgerQuantity,
+			}
+			if err := database.NamedExecContext(ctx, s.log, tx, q, dbDf); err != nil {
+				return fmt.Errorf("namedexeccontext: %w", err)
+			}
+		}
+		return nil
+	}
+
+	if err := database.WithinTran(ctx, s.log, s.db, f); err != nil {
+		return fmt.Errorf("withintran: %w", err)
+	}
+
+	return nil
+}
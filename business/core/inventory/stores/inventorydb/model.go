@@ -0,0 +1,78 @@
+package inventorydb
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/core/inventory"
+)
+
+// dbMovement representa a estrutura que precisamos para mover dados entre a
+// aplicação e a tabela inventory_movements
+type dbMovement struct {
+	ID          uuid.UUID `db:"movement_id"`
+	ProductID   uuid.UUID `db:"product_id"`
+	UserID      uuid.UUID `db:"user_id"`
+	Kind        string    `db:"kind"`
+	Quantity    int       `db:"quantity"`
+	Reason      string    `db:"reason"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+// dbDrift representa uma linha da consulta de divergências
+type dbDrift struct {
+	ProductID      uuid.UUID `db:"product_id"`
+	Quantity       int       `db:"quantity"`
+	LedgerQuantity int       `db:"ledger_quantity"`
+}
+
+// converte uma Movement de domínio em dbMovement para inserir dados no banco
+func toDBMovement(mv inventory.Movement) dbMovement {
+	return dbMovement{
+		ID:          mv.ID,
+		ProductID:   mv.ProductID,
+		UserID:      mv.UserID,
+		Kind:        mv.Kind.Name(),
+		Quantity:    mv.Quantity,
+		Reason:      mv.Reason,
+		DateCreated: mv.DateCreated.UTC(),
+	}
+}
+
+// converte de dbMovement para Movement de domínio para dados que saem do banco
+func toCoreMovement(dbMv dbMovement) inventory.Movement {
+	// o tipo vem de dados gravados pela própria aplicação
+	kind := inventory.MustParseKind(dbMv.Kind)
+
+	return inventory.Movement{
+		ID:          dbMv.ID,
+		ProductID:   dbMv.ProductID,
+		UserID:      dbMv.UserID,
+		Kind:        kind,
+		Quantity:    dbMv.Quantity,
+		Reason:      dbMv.Reason,
+		DateCreated: dbMv.DateCreated.In(time.Local),
+	}
+}
+
+// converte o slice de dbMovement que vem do banco em slice de domínio
+func toCoreMovementSlice(dbMvs []dbMovement) []inventory.Movement {
+	mvs := make([]inventory.Movement, len(dbMvs))
+	for i, dbMv := range dbMvs {
+		mvs[i] = toCoreMovement(dbMv)
+	}
+	return mvs
+}
+
+// converte o slice de dbDrift em slice de domínio
+func toCoreDriftSlice(dbDfs []dbDrift) []inventory.Drift {
+	dfs := make([]inventory.Drift, len(dbDfs))
+	for i, dbDf := range dbDfs {
+		dfs[i] = inventory.Drift{
+			ProductID:      dbDf.ProductID,
+			Quantity:       dbDf.Quantity,
+			LedgerQuantity: dbDf.LedgerQuantity,
+		}
+	}
+	return dfs
+}
@@ -4,18 +4,40 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/data/expr"
+	"github.com/vitoraalmeida/service/business/data/money"
 	"github.com/vitoraalmeida/service/business/sys/validate"
 )
 
+// FilterFields são os campos que podem ser usados na expressão de filtro e
+// seus tipos
+var FilterFields = expr.Fields{
+	"product_id":   expr.UUID,
+	"user_id":      expr.UUID,
+	"name":         expr.String,
+	"cost":         expr.Float,
+	"currency":     expr.String,
+	"quantity":     expr.Int,
+	"sold":         expr.Int,
+	"revenue":      expr.Float,
+	"date_created": expr.Time,
+	"date_updated": expr.Time,
+}
+
 // QueryFilter agrupa os campos disponíveis pelos quais uma consulta pode ser filtrada. É passado por query params numa url
 type QueryFilter struct {
 	// utiliza ponteiros para dar a possibilidade de deixar um ou mais campos vazios (nil)
 	// e podermos passar o objeto inteiro para que seja utilizado com base nos campos
 	// que não forem nulos
-	ID       *uuid.UUID `validate:"omitempty"`
-	Name     *string    `validate:"omitempty,min=3"`
-	Cost     *float64   `validate:"omitempty,numeric"`
-	Quantity *int       `validate:"omitempty,numeric"`
+	ID       *uuid.UUID   `validate:"omitempty"`
+	Name     *string      `validate:"omitempty,min=3"`
+	Cost     *money.Money `validate:"omitempty"`
+	Quantity *int         `validate:"omitempty,numeric"`
+	Category *uuid.UUID   `validate:"omitempty"` // inclui os produtos das subcategorias
+	Tags     []string     `validate:"omitempty"` // produtos que possuem todas as tags
+
+	// condições livres informadas no parâmetro filter
+	Expression expr.Expression
 }
 
 // Validate checa se o dado está no formato correto
@@ -37,7 +59,7 @@ func (qf *QueryFilter) WithName(name string) {
 }
 
 // WithCost define o campo Cost para ser usado no filtro
-func (qf *QueryFilter) WithCost(cost float64) {
+func (qf *QueryFilter) WithCost(cost money.Money) {
 	qf.Cost = &cost
 }
 
@@ -45,3 +67,19 @@ func (qf *QueryFilter) WithCost(cost float64) {
 func (qf *QueryFilter) WithQuantity(quantity int) {
 	qf.Quantity = &quantity
 }
+
+// WithCategoryID define o campo Category para ser usado no filtro
+func (qf *QueryFilter) WithCategoryID(categoryID uuid.UUID) {
+	qf.Category = &categoryID
+}
+
+// WithTags define o campo Tags para ser usado no filtro
+func (qf *QueryFilter) WithTags(tags []string) {
+	qf.Tags = normalizeTags(tags)
+}
+
+// WithExpression define a expressão de filtro que será combinada com os
+// demais campos
+func (qf *QueryFilter) WithExpression(exp expr.Expression) {
+	qf.Expression = exp
+}
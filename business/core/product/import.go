@@ -0,0 +1,262 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/core/category"
+	"github.com/vitoraalmeida/service/business/core/exchange"
+	"github.com/vitoraalmeida/service/business/sys/errs"
+	"github.com/vitoraalmeida/service/business/sys/validate"
+)
+
+// importBatchSize é a quantidade de produtos inseridos por comando no modo
+// atômico
+const importBatchSize = 500
+
+// ErrImportFailed indica que a importação atômica foi desfeita por conter
+// linhas inválidas
+var ErrImportFailed = errs.New(errs.ImportFailed, "import failed")
+
+// ImportMode define o que acontece com a importação quando uma linha é inválida
+type ImportMode int
+
+const (
+	// ImportAtomic insere todos os produtos em uma única transação. Qualquer
+	// linha inválida desfaz a importação inteira
+	ImportAtomic ImportMode = iota
+
+	// ImportTolerant insere cada produto separadamente, ignorando as linhas
+	// inválidas
+	ImportTolerant
+)
+
+// RowReader é a fonte das linhas de uma importação. Next retorna io.EOF
+// quando não há mais linhas. Um erro do tipo validate.FieldErrors indica que
+// apenas a linha atual é inválida e que a leitura pode continuar, qualquer
+// outro erro interrompe a importação
+type RowReader interface {
+	Next() (NewProduct, error)
+}
+
+// ImportReport resume o resultado de uma importação. Os erros são
+// identificados pela linha, contando a partir de 1, no formato row[n].campo
+type ImportReport struct {
+	Imported int
+	Failed   int
+	Errors   validate.FieldErrors
+}
+
+// Import lê os produtos de rr e os insere no banco de acordo com o modo
+// informado. No modo atômico, se alguma linha for inválida nada é inserido,
+// o relatório traz todos os erros encontrados e ErrImportFailed é retornado
+func (c *Core) Import(ctx context.Context, rr RowReader, mode ImportMode) (ImportReport, error) {
+	imp := importer{
+		core:       c,
+		rr:         rr,
+		categories: make(map[uuid.UUID]error),
+		currencies: make(map[string]error),
+		now:        time.Now(),
+	}
+
+	switch mode {
+	case ImportTolerant:
+		return imp.tolerant(ctx)
+	default:
+		return imp.atomic(ctx)
+	}
+}
+
+// =============================================================================
+
+// importer mantém o estado de uma importação em andamento
+type importer struct {
+	core       *Core
+	rr         RowReader
+	row        int
+	report     ImportReport
+	categories map[uuid.UUID]error // categorias já verificadas
+	currencies map[string]error    // moedas já verificadas
+	now        time.Time
+}
+
+// atomic lê as linhas em lotes que são inseridos numa única transação. Ao
+// encontrar uma linha inválida deixa de montar lotes, mas continua lendo
+// para reportar todos os erros de uma vez
+func (imp *importer) atomic(ctx context.Context) (ImportReport, error) {
+	var inserted int
+
+	next := func() ([]Product, error) {
+		batch := make([]Product, 0, importBatchSize)
+
+		for len(batch) < importBatchSize {
+			prd, err := imp.next(ctx)
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					return nil, err
+				}
+				break
+			}
+
+			if len(imp.report.Errors) == 0 {
+				batch = append(batch, prd)
+			}
+		}
+
+		switch {
+		case len(imp.report.Errors) > 0:
+			return nil, ErrImportFailed
+		case len(batch) == 0:
+			return nil, io.EOF
+		}
+
+		inserted += len(batch)
+		return batch, nil
+	}
+
+	if err := imp.core.storer.CreateBatches(ctx, next); err != nil {
+		if errors.Is(err, ErrImportFailed) {
+			return imp.report, ErrImportFailed
+		}
+		return ImportReport{}, fmt.Errorf("import: %w", err)
+	}
+
+	imp.report.Imported = inserted
+
+	return imp.report, nil
+}
+
+// tolerant insere cada linha válida separadamente, registrando no relatório
+// as que falharem
+func (imp *importer) tolerant(ctx context.Context) (ImportReport, error) {
+	for {
+		prd, err := imp.next(ctx)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return imp.report, fmt.Errorf("import: %w", err)
+		}
+
+		// o dono dos produtos é o mesmo em todas as linhas, então um usuário
+		// inexistente interrompe a importação ao invés de falhar cada linha
+		if err := imp.core.storer.Create(ctx, prd); err != nil {
+			switch {
+			case errors.Is(err, ErrUnknownCurrency):
+				imp.rowError("currency", err)
+				continue
+			case errors.Is(err, ErrUnknownCategory):
+				imp.rowError("categoryID", err)
+				continue
+			}
+			return imp.report, fmt.Errorf("import: row[%d]: %w", imp.row, err)
+		}
+
+		imp.report.Imported++
+	}
+
+	return imp.report, nil
+}
+
+// next lê a próxima linha válida, registrando no relatório as linhas
+// inválidas encontradas pelo caminho
+func (imp *importer) next(ctx context.Context) (Product, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return Product{}, err
+		}
+
+		np, err := imp.rr.Next()
+		if errors.Is(err, io.EOF) {
+			return Product{}, io.EOF
+		}
+
+		imp.row++
+
+		if err != nil {
+			fieldErrs := validate.GetFieldErrors(err)
+			if fieldErrs == nil {
+				return Product{}, fmt.Errorf("row[%d]: %w", imp.row, err)
+			}
+			for _, fe := range fieldErrs {
+				imp.report.Errors = append(imp.report.Errors, validate.FieldError{Field: rowField(imp.row, fe.Field), Err: fe.Err})
+			}
+			imp.report.Failed++
+			continue
+		}
+
+		if err := imp.checkCurrency(ctx, np.Cost.Currency()); err != nil {
+			if !errors.Is(err, ErrUnknownCurrency) {
+				return Product{}, fmt.Errorf("row[%d]: %w", imp.row, err)
+			}
+			imp.rowError("currency", err)
+			continue
+		}
+
+		if np.CategoryID != nil {
+			if err := imp.checkCategory(ctx, *np.CategoryID); err != nil {
+				if !errors.Is(err, category.ErrNotFound) {
+					return Product{}, fmt.Errorf("row[%d]: %w", imp.row, err)
+				}
+				imp.rowError("categoryID", err)
+				continue
+			}
+		}
+
+		return newProduct(np, imp.now), nil
+	}
+}
+
+// checkCategory verifica se a categoria existe, consultando o banco apenas
+// uma vez por categoria
+func (imp *importer) checkCategory(ctx context.Context, categoryID uuid.UUID) error {
+	if err, exists := imp.categories[categoryID]; exists {
+		return err
+	}
+
+	_, err := imp.core.catCore.QueryByID(ctx, categoryID)
+	if err != nil && !errors.Is(err, category.ErrNotFound) {
+		return err
+	}
+	imp.categories[categoryID] = err
+
+	return err
+}
+
+// checkCurrency verifica se a moeda tem taxa de câmbio, consultando o banco
+// apenas uma vez por moeda. Assim o modo atômico reporta a linha com a moeda
+// desconhecida ao invés de falhar no meio da transação
+func (imp *importer) checkCurrency(ctx context.Context, currency string) error {
+	if err, exists := imp.currencies[currency]; exists {
+		return err
+	}
+
+	_, err := imp.core.exchCore.QueryByCurrency(ctx, currency)
+	switch {
+	case errors.Is(err, exchange.ErrNotFound):
+		err = ErrUnknownCurrency
+	case err != nil:
+		return err
+	}
+	imp.currencies[currency] = err
+
+	return err
+}
+
+// rowError registra no relatório um erro da linha atual
+func (imp *importer) rowError(field string, err error) {
+	imp.report.Errors = append(imp.report.Errors, validate.FieldError{Field: rowField(imp.row, field), Err: err.Error()})
+	imp.report.Failed++
+}
+
+// rowField identifica o campo de uma linha no relatório
+func rowField(row int, field string) string {
+	if field == "" {
+		return fmt.Sprintf("row[%d]", row)
+	}
+	return fmt.Sprintf("row[%d].%s", row, field)
+}
@@ -4,33 +4,40 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/data/money"
 )
 
 // Product representa um produto individual.
 type Product struct {
 	ID          uuid.UUID
 	Name        string
-	Cost        float64
+	Cost        money.Money
 	Quantity    int
-	Sold        int
-	Revenue     int
-	UserID      uuid.UUID // indica uma relação com User. O usuário que registrou esse produto
+	Sold        int         // unidades vendidas, atualizado ao registrar vendas
+	Revenue     money.Money // total recebido em vendas
+	UserID      uuid.UUID   // indica uma relação com User. O usuário que registrou esse produto
+	CategoryID  *uuid.UUID  // nulo para produtos sem categoria
+	Tags        []string
 	DateCreated time.Time
 	DateUpdated time.Time
 }
 
 // NewProduct representa o modelo de dados que exigimos do cliente para criar um produto
 type NewProduct struct {
-	Name     string
-	Cost     float64
-	Quantity int
-	UserID   uuid.UUID
+	Name       string
+	Cost       money.Money
+	Quantity   int
+	UserID     uuid.UUID
+	CategoryID *uuid.UUID
+	Tags       []string
 }
 
-// UpdateUser  contém informação necessária para atualizar dados de um usuário
+// UpdateProduct contém informação necessária para atualizar dados de um produto.
+// A quantidade em estoque não pode ser alterada por aqui, toda variação deve
+// ser registrada como uma movimentação no pacote inventory
 type UpdateProduct struct {
-	Name     *string  // Usamos a semântica de ponteiro aqui
-	Cost     *float64 // para mostrar que alguns desses dados
-	Quantity *int     // podem ser nulos na tentativa de atualizar
-} // um usuário, pois podemos querer atualizar
-// apenas algum dos dados do usuário
+	Name       *string      // Usamos a semântica de ponteiro aqui para mostrar que
+	Cost       *money.Money // alguns desses dados podem ser nulos na tentativa de
+	CategoryID *uuid.UUID   // atualizar, pois podemos querer atualizar apenas
+	Tags       []string     // algum dos dados
+}
@@ -5,19 +5,29 @@ package product
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/core/category"
+	"github.com/vitoraalmeida/service/business/core/exchange"
 	"github.com/vitoraalmeida/service/business/core/user"
+	"github.com/vitoraalmeida/service/business/data/cursor"
+	"github.com/vitoraalmeida/service/business/data/money"
 	"github.com/vitoraalmeida/service/business/data/order"
+	"github.com/vitoraalmeida/service/business/sys/errs"
 	"go.uber.org/zap"
 )
 
 // Conjunto de erros para operações CRUD
 var (
-	ErrNotFound = errors.New("product not found")
+	ErrNotFound        = errs.New(errs.ProductNotFound, "product not found")
+	ErrUnknownCurrency = errs.New(errs.UnknownCurrency, "currency has no exchange rate")
+	ErrUnknownCategory = errs.New(errs.CategoryNotFound, "category not found")
+	ErrUnknownUser     = errs.New(errs.UserNotFound, "product owner not found")
+	ErrInUse           = errs.New(errs.ProductInUse, "product has sales or inventory history")
 )
 
 // Abstrai qual é a implementação de fato que vai gerenciar a interção
@@ -27,26 +37,33 @@ type Storer interface {
 	Update(ctx context.Context, prd Product) error
 	Delete(ctx context.Context, prd Product) error
 	Query(ctx context.Context, filter QueryFilter, orderBy order.By, pageNumber int, rowsPerPage int) ([]Product, error)
+	QueryByCursor(ctx context.Context, filter QueryFilter, orderBy order.By, cur cursor.Cursor, rowsPerPage int) ([]Product, cursor.Page, error)
 	Count(ctx context.Context, filter QueryFilter) (int, error)
 	QueryByID(ctx context.Context, productID uuid.UUID) (Product, error)
 	QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Product, error)
+	CreateBatches(ctx context.Context, next func() ([]Product, error)) error
+	QueryEach(ctx context.Context, filter QueryFilter, orderBy order.By, fn func(Product) error) error
 }
 
 // Core é a API para o domínio Product, gerencia as ações num produto
 type Core struct {
 	// Abstrai qual é a implementação de fato que vai gerenciar a interção
 	// com o armazenamento de usuário
-	log     *zap.SugaredLogger
-	usrCore *user.Core // Usamos a api de Users, pois há uma relação entre Produtos e usuários
-	storer  Storer
+	log      *zap.SugaredLogger
+	usrCore  *user.Core     // Usamos a api de Users, pois há uma relação entre Produtos e usuários
+	catCore  *category.Core // usado para validar a categoria do produto
+	exchCore *exchange.Core // usado para validar a moeda dos produtos importados
+	storer   Storer
 }
 
 // NewCore constrói Core para uso da API de produtos
-func NewCore(log *zap.SugaredLogger, usrCore *user.Core, storer Storer) *Core {
+func NewCore(log *zap.SugaredLogger, usrCore *user.Core, catCore *category.Core, exchCore *exchange.Core, storer Storer) *Core {
 	core := Core{
-		log:     log,
-		usrCore: usrCore, // usrCore pode ser usado aqui, pois o modelo de usuário é usado no modelo de product
-		storer:  storer,
+		log:      log,
+		usrCore:  usrCore, // usrCore pode ser usado aqui, pois o modelo de usuário é usado no modelo de product
+		catCore:  catCore,
+		exchCore: exchCore,
+		storer:   storer,
 	}
 
 	return &core
@@ -55,18 +72,14 @@ func NewCore(log *zap.SugaredLogger, usrCore *user.Core, storer Storer) *Core {
 // Create insere um novo produto no banco de dados, retornando o produto com o ID que foi gerado pelo sistema
 // semantica de ponteiro para APIs         semantica de valor para Dados e para interfaces (context.Context)
 func (c *Core) Create(ctx context.Context, np NewProduct) (Product, error) {
-	now := time.Now()
-
-	prd := Product{
-		ID:          uuid.New(),
-		Name:        np.Name,
-		Cost:        np.Cost,
-		Quantity:    np.Quantity,
-		UserID:      np.UserID,
-		DateCreated: now,
-		DateUpdated: now,
+	if np.CategoryID != nil {
+		if _, err := c.catCore.QueryByID(ctx, *np.CategoryID); err != nil {
+			return Product{}, fmt.Errorf("create: %w", err)
+		}
 	}
 
+	prd := newProduct(np, time.Now())
+
 	if err := c.storer.Create(ctx, prd); err != nil {
 		return Product{}, fmt.Errorf("create: %w", err)
 	}
@@ -81,10 +94,20 @@ func (c *Core) Update(ctx context.Context, prd Product, up UpdateProduct) (Produ
 		prd.Name = *up.Name
 	}
 	if up.Cost != nil {
+		// a receita acumulada está na moeda do produto, que não pode mudar
+		if up.Cost.Currency() != prd.Cost.Currency() {
+			return Product{}, fmt.Errorf("update: cost: %w", money.ErrCurrencyMismatch)
+		}
 		prd.Cost = *up.Cost
 	}
-	if up.Quantity != nil {
-		prd.Quantity = *up.Quantity
+	if up.CategoryID != nil {
+		if _, err := c.catCore.QueryByID(ctx, *up.CategoryID); err != nil {
+			return Product{}, fmt.Errorf("update: %w", err)
+		}
+		prd.CategoryID = up.CategoryID
+	}
+	if up.Tags != nil {
+		prd.Tags = normalizeTags(up.Tags)
 	}
 	prd.DateUpdated = time.Now()
 
@@ -113,6 +136,17 @@ func (c *Core) Query(ctx context.Context, filter QueryFilter, orderBy order.By,
 	return prds, nil
 }
 
+// QueryByCursor busca uma página de produtos a partir da posição de um cursor
+// e os cursores para as páginas vizinhas
+func (c *Core) QueryByCursor(ctx context.Context, filter QueryFilter, orderBy order.By, cur cursor.Cursor, rowsPerPage int) ([]Product, cursor.Page, error) {
+	prds, page, err := c.storer.QueryByCursor(ctx, filter, orderBy, cur, rowsPerPage)
+	if err != nil {
+		return nil, cursor.Page{}, fmt.Errorf("querybycursor: %w", err)
+	}
+
+	return prds, page, nil
+}
+
 // Count retorna o numero total de produtos no banco
 func (c *Core) Count(ctx context.Context, filter QueryFilter) (int, error) {
 	return c.storer.Count(ctx, filter)
@@ -136,3 +170,55 @@ func (c *Core) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Product,
 
 	return prds, nil
 }
+
+// QueryEach chama fn para cada produto que atende ao filtro, na ordem
+// informada, sem carregar todos os produtos em memória
+func (c *Core) QueryEach(ctx context.Context, filter QueryFilter, orderBy order.By, fn func(Product) error) error {
+	if err := c.storer.QueryEach(ctx, filter, orderBy, fn); err != nil {
+		return fmt.Errorf("queryeach: %w", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// newProduct monta um produto novo a partir dos dados informados pelo cliente
+func newProduct(np NewProduct, now time.Time) Product {
+	return Product{
+		ID:          uuid.New(),
+		Name:        np.Name,
+		Cost:        np.Cost,
+		Quantity:    np.Quantity,
+		Revenue:     money.New(0, np.Cost.Currency()),
+		UserID:      np.UserID,
+		CategoryID:  np.CategoryID,
+		Tags:        normalizeTags(np.Tags),
+		DateCreated: now,
+		DateUpdated: now,
+	}
+}
+
+// normalizeTags deixa as tags em minúsculas, sem espaços nas pontas e sem
+// repetições, para que a busca por tags não dependa da forma como foram
+// digitadas
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		if _, exists := seen[tag]; exists {
+			continue
+		}
+		seen[tag] = struct{}{}
+		normalized = append(normalized, tag)
+	}
+
+	slices.Sort(normalized)
+
+	return normalized
+}
@@ -0,0 +1,82 @@
+package productdb
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/vitoraalmeida/service/business/core/product"
+	"github.com/vitoraalmeida/service/business/sys/database/pgx/dbarray"
+)
+
+// exprColumns mapeia os campos aceitos na expressão de filtro
+// (product.FilterFields) para as colunas da tabela
+var exprColumns = map[string]string{
+	"product_id":   "product_id",
+	"user_id":      "user_id",
+	"name":         "name",
+	"cost":         "cost",
+	"currency":     "currency",
+	"quantity":     "quantity",
+	"sold":         "sold",
+	"revenue":      "revenue",
+	"date_created": "date_created",
+	"date_updated": "date_updated",
+}
+
+// applyFilter adiciona na query a parte do WHERE com base nos campos não nulos
+// do filtro. Segue a mesma lógica de userdb.applyFilter
+func (s *Store) applyFilter(filter product.QueryFilter, data map[string]interface{}, buf *bytes.Buffer, wc ...string) error {
+	if filter.ID != nil {
+		data["product_id"] = *filter.ID
+		wc = append(wc, "product_id = :product_id")
+	}
+
+	if filter.Name != nil {
+		data["name"] = fmt.Sprintf("%%%s%%", *filter.Name)
+		wc = append(wc, "name LIKE :name")
+	}
+
+	if filter.Cost != nil {
+		data["cost"] = *filter.Cost
+		wc = append(wc, "cost = :cost")
+	}
+
+	if filter.Quantity != nil {
+		data["quantity"] = *filter.Quantity
+		wc = append(wc, "quantity = :quantity")
+	}
+
+	// UNION descarta as categorias repetidas, então a subárvore termina mesmo
+	// que a árvore tenha um ciclo
+	if filter.Category != nil {
+		data["category_id"] = *filter.Category
+		wc = append(wc, `category_id IN (
+		WITH RECURSIVE subtree AS (
+			SELECT category_id FROM categories WHERE category_id = :category_id
+			UNION
+			SELECT c.category_id FROM categories AS c JOIN subtree AS s ON c.parent_id = s.category_id
+		)
+		SELECT category_id FROM subtree)`)
+	}
+
+	if len(filter.Tags) > 0 {
+		data["tags"] = dbarray.String(filter.Tags)
+		wc = append(wc, "tags @> :tags")
+	}
+
+	if filter.Expression != nil {
+		clauses, err := filter.Expression.Clauses(exprColumns, data)
+		if err != nil {
+			return fmt.Errorf("expression: %w", err)
+		}
+		wc = append(wc, clauses...)
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package productdb
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/core/product"
+	"github.com/vitoraalmeida/service/business/data/money"
+	"github.com/vitoraalmeida/service/business/sys/database/pgx/dbarray"
+)
+
+// dbProduct representa a estrutura que precisamos para mover dados entre a
+// aplicação e o banco de dados
+type dbProduct struct {
+	ID          uuid.UUID      `db:"product_id"`
+	UserID      uuid.UUID      `db:"user_id"`
+	Name        string         `db:"name"`
+	Cost        money.Money    `db:"cost"`
+	Currency    string         `db:"currency"`
+	Quantity    int            `db:"quantity"`
+	Sold        int            `db:"sold"`
+	Revenue     money.Money    `db:"revenue"`
+	CategoryID  uuid.NullUUID  `db:"category_id"`
+	Tags        dbarray.String `db:"tags"`
+	DateCreated time.Time      `db:"date_created"`
+	DateUpdated time.Time      `db:"date_updated"`
+}
+
+// converte um Product de domínio em dbProduct para inserir dados no banco
+func toDBProduct(prd product.Product) dbProduct {
+	var categoryID uuid.NullUUID
+	if prd.CategoryID != nil {
+		categoryID = uuid.NullUUID{UUID: *prd.CategoryID, Valid: true}
+	}
+
+	// a coluna não aceita nulo, então a ausência de tags é um array vazio
+	tags := dbarray.String{}
+	if prd.Tags != nil {
+		tags = prd.Tags
+	}
+
+	return dbProduct{
+		ID:          prd.ID,
+		UserID:      prd.UserID,
+		Name:        prd.Name,
+		Cost:        prd.Cost,
+		Currency:    prd.Cost.Currency(),
+		Quantity:    prd.Quantity,
+		Sold:        prd.Sold,
+		Revenue:     prd.Revenue,
+		CategoryID:  categoryID,
+		Tags:        tags,
+		DateCreated: prd.DateCreated.UTC(),
+		DateUpdated: prd.DateUpdated.UTC(),
+	}
+}
+
+// converte de dbProduct para Product de domínio para dados que saem do banco.
+// Custo e receita estão na moeda do produto
+func toCoreProduct(dbPrd dbProduct) product.Product {
+	var categoryID *uuid.UUID
+	if dbPrd.CategoryID.Valid {
+		id := dbPrd.CategoryID.UUID
+		categoryID = &id
+	}
+
+	return product.Product{
+		ID:          dbPrd.ID,
+		UserID:      dbPrd.UserID,
+		Name:        dbPrd.Name,
+		Cost:        money.New(dbPrd.Cost.Minor(), dbPrd.Currency),
+		Quantity:    dbPrd.Quantity,
+		Sold:        dbPrd.Sold,
+		Revenue:     money.New(dbPrd.Revenue.Minor(), dbPrd.Currency),
+		CategoryID:  categoryID,
+		Tags:        dbPrd.Tags,
+		DateCreated: dbPrd.DateCreated.In(time.Local),
+		DateUpdated: dbPrd.DateUpdated.In(time.Local),
+	}
+}
+
+// converte o slice de dbProduct que vem do banco em slice de produtos de domínio
+func toCoreProductSlice(dbPrds []dbProduct) []product.Product {
+	prds := make([]product.Product, len(dbPrds))
+	for i, dbPrd := range dbPrds {
+		prds[i] = toCoreProduct(dbPrd)
+	}
+	return prds
+}
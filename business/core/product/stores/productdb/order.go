@@ -0,0 +1,113 @@
+package productdb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vitoraalmeida/service/business/core/product"
+	"github.com/vitoraalmeida/service/business/data/cursor"
+	"github.com/vitoraalmeida/service/business/data/order"
+)
+
+var orderByFields = map[string]string{
+	product.OrderByProdID:   "product_id",
+	product.OrderByName:     "name",
+	product.OrderByCost:     "cost",
+	product.OrderByQuantity: "quantity",
+	product.OrderBySold:     "sold",
+	product.OrderByRevenue:  "revenue",
+	product.OrderByUserID:   "user_id",
+}
+
+// tipo de cada coluna de ordenação, usado para converter o valor guardado no
+// cursor ao comparar com a coluna
+var orderByTypes = map[string]string{
+	product.OrderByProdID:   "UUID",
+	product.OrderByName:     "TEXT",
+	product.OrderByCost:     "NUMERIC",
+	product.OrderByQuantity: "INT",
+	product.OrderBySold:     "INT",
+	product.OrderByRevenue:  "NUMERIC",
+	product.OrderByUserID:   "UUID",
+}
+
+// orderByKeys retorna os campos da ordenação terminando sempre na chave
+// primária, para que a ordem das linhas seja determinística
+func orderByKeys(orderBy order.By) ([]order.Field, error) {
+	fields := make([]order.Field, 0, len(orderBy.Fields)+1)
+
+	for _, fld := range orderBy.Fields {
+		if _, exists := orderByFields[fld.Name]; !exists {
+			return nil, fmt.Errorf("field %q does not exist", fld.Name)
+		}
+
+		fields = append(fields, fld)
+
+		// nenhum campo depois da chave primária altera a ordem
+		if fld.Name == product.OrderByProdID {
+			return fields, nil
+		}
+	}
+
+	return append(fields, order.Field{Name: product.OrderByProdID, Direction: order.ASC}), nil
+}
+
+// adiciona na query que vai ser executada a parte da ordenação
+func orderByClause(orderBy order.By) (string, error) {
+	fields, err := orderByKeys(orderBy)
+	if err != nil {
+		return "", err
+	}
+
+	parts := make([]string, len(fields))
+	for i, fld := range fields {
+		parts[i] = orderByFields[fld.Name] + " " + fld.Direction
+	}
+
+	return " ORDER BY " + strings.Join(parts, ", "), nil
+}
+
+// =============================================================================
+
+// cursorColumns descreve as colunas da ordenação para a consulta por cursor
+func cursorColumns(fields []order.Field) []cursor.Column {
+	columns := make([]cursor.Column, len(fields))
+	for i, fld := range fields {
+		columns[i] = cursor.Column{
+			Name:      orderByFields[fld.Name],
+			Type:      orderByTypes[fld.Name],
+			Direction: fld.Direction,
+		}
+	}
+
+	return columns
+}
+
+// cursorKey gera o cursor que aponta para a linha na ordenação
+func cursorKey(dbPrd dbProduct, orderBy order.By, fields []order.Field) cursor.Cursor {
+	values := make([]string, len(fields))
+	for i, fld := range fields {
+		switch fld.Name {
+		case product.OrderByProdID:
+			values[i] = dbPrd.ID.String()
+		case product.OrderByName:
+			values[i] = dbPrd.Name
+		case product.OrderByCost:
+			values[i] = dbPrd.Cost.String()
+		case product.OrderByQuantity:
+			values[i] = strconv.Itoa(dbPrd.Quantity)
+		case product.OrderBySold:
+			values[i] = strconv.Itoa(dbPrd.Sold)
+		case product.OrderByRevenue:
+			values[i] = dbPrd.Revenue.String()
+		case product.OrderByUserID:
+			values[i] = dbPrd.UserID.String()
+		}
+	}
+
+	return cursor.Cursor{
+		Order:  orderBy.String(),
+		Values: values,
+	}
+}
@@ -0,0 +1,411 @@
+// Package productdb contém as funcionalidades CRUD relacionadas a produtos.
+package productdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/vitoraalmeida/service/business/core/product"
+	"github.com/vitoraalmeida/service/business/data/cursor"
+	"github.com/vitoraalmeida/service/business/data/order"
+	database "github.com/vitoraalmeida/service/business/sys/database/pgx"
+	"go.uber.org/zap"
+)
+
+// Store gerencia o conjunto de API que usamos para interagir com o banco de dados
+type Store struct {
+	log *zap.SugaredLogger
+	db  *sqlx.DB
+}
+
+// NewStore constrói a API de acesso aos dados de produtos
+func NewStore(log *zap.SugaredLogger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Create insere um novo produto no banco. A quantidade inicial é registrada
+// no histórico de estoque na mesma transação, para que o produto nasça
+// conciliado com o histórico
+func (s *Store) Create(ctx context.Context, prd product.Product) error {
+	const q = `
+	INSERT INTO products
+		(product_id, user_id, name, cost, currency, quantity, category_id, tags, date_created, date_updated)
+	VALUES
+		(:product_id, :user_id, :name, :cost, :currency, :quantity, :category_id, :tags, :date_created, :date_updated)`
+
+	const qMovement = `
+	INSERT INTO inventory_movements
+		(movement_id, product_id, user_id, kind, quantity, reason, date_created)
+	VALUES
+		(:movement_id, :product_id, :user_id, 'RESTOCK', :quantity, 'initial stock', :date_created)`
+
+	dbPrd := toDBProduct(prd)
+
+	f := func(tx *sqlx.Tx) error {
+		if err := database.NamedExecContext(ctx, s.log, tx, q, dbPrd); err != nil {
+			return fmt.Errorf("namedexeccontext: %w", foreignKeyError(err))
+		}
+
+		if dbPrd.Quantity == 0 {
+			return nil
+		}
+
+		data := map[string]interface{}{
+			"movement_id":  uuid.New(),
+			"product_id":   dbPrd.ID,
+			"user_id":      dbPrd.UserID,
+			"quantity":     dbPrd.Quantity,
+			"date_created": dbPrd.DateCreated,
+		}
+		if err := database.NamedExecContext(ctx, s.log, tx, qMovement, data); err != nil {
+			return fmt.Errorf("namedexeccontext: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := database.WithinTran(ctx, s.log, s.db, f); err != nil {
+		return fmt.Errorf("withintran: %w", err)
+	}
+
+	return nil
+}
+
+// CreateBatches insere, numa única transação, os lotes de produtos retornados
+// por next até que ele retorne io.EOF. Qualquer outro erro de next desfaz
+// tudo o que já foi inserido. Assim como em Create, a quantidade inicial de
+// cada produto é registrada no histórico de estoque
+func (s *Store) CreateBatches(ctx context.Context, next func() ([]product.Product, error)) error {
+	const q = `
+	INSERT INTO products
+		(product_id, user_id, name, cost, currency, quantity, category_id, tags, date_created, date_updated)
+	VALUES
+		(:product_id, :user_id, :name, :cost, :currency, :quantity, :category_id, :tags, :date_created, :date_updated)`
+
+	const qMovement = `
+	INSERT INTO inventory_movements
+		(movement_id, product_id, user_id, kind, quantity, reason, date_created)
+	VALUES
+		(:movement_id, :product_id, :user_id, 'RESTOCK', :quantity, 'initial stock', :date_created)`
+
+	type dbMovement struct {
+		ID          uuid.UUID `db:"movement_id"`
+		ProductID   uuid.UUID `db:"product_id"`
+		UserID      uuid.UUID `db:"user_id"`
+		Quantity    int       `db:"quantity"`
+		DateCreated time.Time `db:"date_created"`
+	}
+
+	f := func(tx *sqlx.Tx) error {
+		for {
+			prds, err := next()
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+
+			dbPrds := make([]dbProduct, len(prds))
+			var dbMvts []dbMovement
+			for i, prd := range prds {
+				dbPrds[i] = toDBProduct(prd)
+				if prd.Quantity != 0 {
+					dbMvts = append(dbMvts, dbMovement{
+						ID:          uuid.New(),
+						ProductID:   dbPrds[i].ID,
+						UserID:      dbPrds[i].UserID,
+						Quantity:    dbPrds[i].Quantity,
+						DateCreated: dbPrds[i].DateCreated,
+					})
+				}
+			}
+
+			if err := database.NamedExecContext(ctx, s.log, tx, q, dbPrds); err != nil {
+				return fmt.Errorf("namedexeccontext: %w", foreignKeyError(err))
+			}
+
+			if len(dbMvts) == 0 {
+				continue
+			}
+
+			if err := database.NamedExecContext(ctx, s.log, tx, qMovement, dbMvts); err != nil {
+				return fmt.Errorf("namedexeccontext: %w", err)
+			}
+		}
+	}
+
+	if err := database.WithinTran(ctx, s.log, s.db, f); err != nil {
+		return fmt.Errorf("withintran: %w", err)
+	}
+
+	return nil
+}
+
+// Update substitui o produto no banco de dados
+func (s *Store) Update(ctx context.Context, prd product.Product) error {
+	const q = `
+	UPDATE
+		products
+	SET
+		"name" = :name,
+		"cost" = :cost,
+		"category_id" = :category_id,
+		"tags" = :tags,
+		"date_updated" = :date_updated
+	WHERE
+		product_id = :product_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, toDBProduct(prd)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", foreignKeyError(err))
+	}
+
+	return nil
+}
+
+// Delete remove um produto do banco de dados. Produtos com vendas ou
+// movimentações de estoque não podem ser removidos, para preservar o histórico
+func (s *Store) Delete(ctx context.Context, prd product.Product) error {
+	data := struct {
+		ID string `db:"product_id"`
+	}{
+		ID: prd.ID.String(),
+	}
+
+	const q = `
+	DELETE FROM
+		products
+	WHERE
+		product_id = :product_id`
+
+	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		if errors.Is(err, database.ErrDBForeignKey) {
+			return fmt.Errorf("namedexeccontext: %w", product.ErrInUse)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Query busca uma lista de produtos existentes no banco
+func (s *Store) Query(ctx context.Context, filter product.QueryFilter, orderBy order.By, pageNumber int, rowsPerPage int) ([]product.Product, error) {
+	data := map[string]interface{}{
+		"offset":        (pageNumber - 1) * rowsPerPage,
+		"rows_per_page": rowsPerPage,
+	}
+
+	const q = `
+	SELECT
+		product_id, user_id, name, cost, currency, quantity, sold, revenue, category_id, tags, date_created, date_updated
+	FROM
+		products`
+
+	buf := bytes.NewBufferString(q)
+	if err := s.applyFilter(filter, data, buf); err != nil {
+		return nil, err
+	}
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbPrds []dbProduct
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbPrds); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreProductSlice(dbPrds), nil
+}
+
+// QueryByCursor busca uma página de produtos a partir da posição do cursor,
+// sem usar OFFSET. Retorna também os cursores das páginas vizinhas
+func (s *Store) QueryByCursor(ctx context.Context, filter product.QueryFilter, orderBy order.By, cur cursor.Cursor, rowsPerPage int) ([]product.Product, cursor.Page, error) {
+	// buscamos uma linha a mais para saber se existe mais uma página
+	data := map[string]interface{}{
+		"rows_per_page": rowsPerPage + 1,
+	}
+
+	const q = `
+	SELECT
+		product_id, user_id, name, cost, currency, quantity, sold, revenue, category_id, tags, date_created, date_updated
+	FROM
+		products`
+
+	fields, err := orderByKeys(orderBy)
+	if err != nil {
+		return nil, cursor.Page{}, err
+	}
+	columns := cursorColumns(fields)
+
+	var wc []string
+	if !cur.IsZero() {
+		clause, err := cursor.Clause(columns, cur, data)
+		if err != nil {
+			return nil, cursor.Page{}, err
+		}
+		wc = append(wc, clause)
+	}
+
+	buf := bytes.NewBufferString(q)
+	if err := s.applyFilter(filter, data, buf, wc...); err != nil {
+		return nil, cursor.Page{}, err
+	}
+
+	buf.WriteString(cursor.OrderByClause(columns, cur))
+	buf.WriteString(" FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbPrds []dbProduct
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbPrds); err != nil {
+		return nil, cursor.Page{}, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	if cur.Direction == cursor.Prev {
+		slices.Reverse(dbPrds)
+	}
+
+	dbPrds, page := cursor.Paginate(dbPrds, cur, rowsPerPage, func(dbPrd dbProduct) cursor.Cursor {
+		return cursorKey(dbPrd, orderBy, fields)
+	})
+
+	return toCoreProductSlice(dbPrds), page, nil
+}
+
+// QueryEach busca os produtos que atendem ao filtro, entregando um de cada vez
+// para fn conforme são lidos do banco
+func (s *Store) QueryEach(ctx context.Context, filter product.QueryFilter, orderBy order.By, fn func(product.Product) error) error {
+	data := map[string]interface{}{}
+
+	const q = `
+	SELECT
+		product_id, user_id, name, cost, currency, quantity, sold, revenue, category_id, tags, date_created, date_updated
+	FROM
+		products`
+
+	buf := bytes.NewBufferString(q)
+	if err := s.applyFilter(filter, data, buf); err != nil {
+		return err
+	}
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return err
+	}
+	buf.WriteString(orderByClause)
+
+	f := func(dbPrd dbProduct) error {
+		return fn(toCoreProduct(dbPrd))
+	}
+
+	if err := database.NamedQueryEach(ctx, s.log, s.db, buf.String(), data, f); err != nil {
+		return fmt.Errorf("namedqueryeach: %w", err)
+	}
+
+	return nil
+}
+
+// Count retorna o total de produtos no banco
+func (s *Store) Count(ctx context.Context, filter product.QueryFilter) (int, error) {
+	data := map[string]interface{}{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		products`
+
+	buf := bytes.NewBufferString(q)
+	if err := s.applyFilter(filter, data, buf); err != nil {
+		return 0, err
+	}
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryByID busca o produto especificado
+func (s *Store) QueryByID(ctx context.Context, productID uuid.UUID) (product.Product, error) {
+	data := struct {
+		ID string `db:"product_id"`
+	}{
+		ID: productID.String(),
+	}
+
+	const q = `
+	SELECT
+		product_id, user_id, name, cost, currency, quantity, sold, revenue, category_id, tags, date_created, date_updated
+	FROM
+		products
+	WHERE
+		product_id = :product_id`
+
+	var dbPrd dbProduct
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbPrd); err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return product.Product{}, fmt.Errorf("namedquerystruct: %w", product.ErrNotFound)
+		}
+		return product.Product{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreProduct(dbPrd), nil
+}
+
+// QueryByUserID busca os produtos registrados pelo usuário especificado
+func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]product.Product, error) {
+	data := struct {
+		ID string `db:"user_id"`
+	}{
+		ID: userID.String(),
+	}
+
+	const q = `
+	SELECT
+		product_id, user_id, name, cost, currency, quantity, sold, revenue, category_id, tags, date_created, date_updated
+	FROM
+		products
+	WHERE
+		user_id = :user_id`
+
+	var dbPrds []dbProduct
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbPrds); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreProductSlice(dbPrds), nil
+}
+
+// foreignKeyError converte a violação de chave estrangeira no erro da relação
+// que falhou, pela constraint violada. Outros erros são mantidos
+func foreignKeyError(err error) error {
+	switch database.ForeignKeyConstraint(err) {
+	case "":
+		return err
+	case "products_currency_fkey":
+		return product.ErrUnknownCurrency
+	case "products_category_id_fkey":
+		return product.ErrUnknownCategory
+	case "products_user_id_fkey":
+		return product.ErrUnknownUser
+	}
+	return err
+}
@@ -0,0 +1,73 @@
+package sale
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/data/expr"
+	"github.com/vitoraalmeida/service/business/sys/validate"
+)
+
+// FilterFields são os campos que podem ser usados na expressão de filtro e
+// seus tipos
+var FilterFields = expr.Fields{
+	"sale_id":      expr.UUID,
+	"user_id":      expr.UUID,
+	"total":        expr.Float,
+	"date_created": expr.Time,
+}
+
+// QueryFilter agrupa os campos disponíveis pelos quais uma consulta de vendas
+// pode ser filtrada
+type QueryFilter struct {
+	ID               *uuid.UUID `validate:"omitempty"`
+	UserID           *uuid.UUID `validate:"omitempty"`
+	ProductID        *uuid.UUID `validate:"omitempty"` // vendas que contenham o produto
+	StartCreatedDate *time.Time `validate:"omitempty"`
+	EndCreatedDate   *time.Time `validate:"omitempty"`
+
+	// condições livres informadas no parâmetro filter
+	Expression expr.Expression
+}
+
+// Validate checa se o dado está no formato correto
+func (qf *QueryFilter) Validate() error {
+	if err := validate.Check(qf); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+	return nil
+}
+
+// WithSaleID define o campo ID para ser usado no filtro
+func (qf *QueryFilter) WithSaleID(saleID uuid.UUID) {
+	qf.ID = &saleID
+}
+
+// WithUserID define o campo UserID para ser usado no filtro
+func (qf *QueryFilter) WithUserID(userID uuid.UUID) {
+	qf.UserID = &userID
+}
+
+// WithProductID define o campo ProductID para ser usado no filtro
+func (qf *QueryFilter) WithProductID(productID uuid.UUID) {
+	qf.ProductID = &productID
+}
+
+// WithStartDateCreated define o campo StartCreatedDate para ser usado no filtro
+func (qf *QueryFilter) WithStartDateCreated(startDate time.Time) {
+	d := startDate.UTC()
+	qf.StartCreatedDate = &d
+}
+
+// WithEndCreatedDate define o campo EndCreatedDate para ser usado no filtro
+func (qf *QueryFilter) WithEndCreatedDate(endDate time.Time) {
+	d := endDate.UTC()
+	qf.EndCreatedDate = &d
+}
+
+// WithExpression define a expressão de filtro que será combinada com os
+// demais campos
+func (qf *QueryFilter) WithExpression(exp expr.Expression) {
+	qf.Expression = exp
+}
@@ -0,0 +1,38 @@
+package sale
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/data/money"
+)
+
+// Sale representa uma venda registrada por um usuário
+type Sale struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID // usuário que registrou a venda
+	Items       []Item
+	Total       money.Money // sempre na moeda padrão
+	DateCreated time.Time
+}
+
+// Item representa um produto vendido numa venda. Price guarda o custo do
+// produto no momento da venda, na moeda do produto, para que alterações
+// posteriores não mudem o histórico
+type Item struct {
+	ProductID uuid.UUID
+	Quantity  int
+	Price     money.Money
+}
+
+// NewSale contém informação necessária para registrar uma venda
+type NewSale struct {
+	UserID uuid.UUID
+	Items  []NewItem
+}
+
+// NewItem contém informação necessária para adicionar um produto numa venda
+type NewItem struct {
+	ProductID uuid.UUID
+	Quantity  int
+}
@@ -0,0 +1,15 @@
+package sale
+
+import "github.com/vitoraalmeida/service/business/data/order"
+
+// DefaultOrderBy representa a forma padrão de ordenação. As vendas mais
+// recentes aparecem primeiro
+var DefaultOrderBy = order.NewBy(OrderByDateCreated, order.DESC)
+
+// Conjunto de campos que podem ser usado para ordenar os resultados
+const (
+	OrderBySaleID      = "saleid"
+	OrderByUserID      = "userid"
+	OrderByTotal       = "total"
+	OrderByDateCreated = "datecreated"
+)
@@ -0,0 +1,135 @@
+// Package sale fornece a API de negócio para registrar e consultar vendas.
+// Registrar uma venda dá baixa no estoque dos produtos vendidos.
+package sale
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/core/exchange"
+	"github.com/vitoraalmeida/service/business/core/product"
+	"github.com/vitoraalmeida/service/business/data/money"
+	"github.com/vitoraalmeida/service/business/data/order"
+	"github.com/vitoraalmeida/service/business/sys/errs"
+	"go.uber.org/zap"
+)
+
+// Conjunto de erros para operações com vendas
+var (
+	ErrNotFound          = errs.New(errs.SaleNotFound, "sale not found")
+	ErrInsufficientStock = errs.New(errs.InsufficientStock, "insufficient stock for product")
+)
+
+// Storer abstrai qual é a implementação de fato que vai gerenciar a interação
+// com o armazenamento de vendas
+type Storer interface {
+	// Create deve registrar a venda e dar baixa no estoque de forma atômica,
+	// retornando ErrInsufficientStock se algum produto não tiver a quantidade
+	// necessária
+	Create(ctx context.Context, sl Sale) error
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, pageNumber int, rowsPerPage int) ([]Sale, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, saleID uuid.UUID) (Sale, error)
+}
+
+// Core é a API para o domínio Sale
+type Core struct {
+	log      *zap.SugaredLogger
+	prdCore  *product.Core  // usado para consultar o preço dos produtos vendidos
+	exchCore *exchange.Core // usado para somar produtos de moedas diferentes
+	storer   Storer
+}
+
+// NewCore constrói Core para uso da API de vendas
+func NewCore(log *zap.SugaredLogger, prdCore *product.Core, exchCore *exchange.Core, storer Storer) *Core {
+	return &Core{
+		log:      log,
+		prdCore:  prdCore,
+		exchCore: exchCore,
+		storer:   storer,
+	}
+}
+
+// Create registra uma nova venda. O preço de cada item é o custo atual do
+// produto e o total é convertido para a moeda padrão. Retorna
+// ErrInsufficientStock se algum produto não tiver estoque suficiente, e nesse
+// caso nada é alterado
+func (c *Core) Create(ctx context.Context, ns NewSale) (Sale, error) {
+	rates, err := c.exchCore.Rates(ctx)
+	if err != nil {
+		return Sale{}, fmt.Errorf("create: %w", err)
+	}
+
+	items := make([]Item, len(ns.Items))
+	total := money.New(0, money.DefaultCurrency)
+
+	for i, ni := range ns.Items {
+		prd, err := c.prdCore.QueryByID(ctx, ni.ProductID)
+		if err != nil {
+			return Sale{}, fmt.Errorf("create: %w", err)
+		}
+
+		// checagem antecipada, a garantia de fato é feita pelo storer dentro
+		// da transação
+		if prd.Quantity < ni.Quantity {
+			return Sale{}, fmt.Errorf("create: productID[%s]: %w", prd.ID, ErrInsufficientStock)
+		}
+
+		items[i] = Item{
+			ProductID: prd.ID,
+			Quantity:  ni.Quantity,
+			Price:     prd.Cost,
+		}
+
+		subtotal, err := rates.Convert(prd.Cost.Mul(int64(ni.Quantity)), money.DefaultCurrency)
+		if err != nil {
+			return Sale{}, fmt.Errorf("create: productID[%s]: %w", prd.ID, err)
+		}
+
+		total, err = total.Add(subtotal)
+		if err != nil {
+			return Sale{}, fmt.Errorf("create: productID[%s]: %w", prd.ID, err)
+		}
+	}
+
+	sl := Sale{
+		ID:          uuid.New(),
+		UserID:      ns.UserID,
+		Items:       items,
+		Total:       total,
+		DateCreated: time.Now(),
+	}
+
+	if err := c.storer.Create(ctx, sl); err != nil {
+		return Sale{}, fmt.Errorf("create: %w", err)
+	}
+
+	return sl, nil
+}
+
+// Query busca as vendas com paginação
+func (c *Core) Query(ctx context.Context, filter QueryFilter, orderBy order.By, pageNumber int, rowsPerPage int) ([]Sale, error) {
+	sls, err := c.storer.Query(ctx, filter, orderBy, pageNumber, rowsPerPage)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return sls, nil
+}
+
+// Count retorna o numero total de vendas que atendem ao filtro
+func (c *Core) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	return c.storer.Count(ctx, filter)
+}
+
+// QueryByID busca a venda especificada com seus itens
+func (c *Core) QueryByID(ctx context.Context, saleID uuid.UUID) (Sale, error) {
+	sl, err := c.storer.QueryByID(ctx, saleID)
+	if err != nil {
+		return Sale{}, fmt.Errorf("query: saleID[%s]: %w", saleID, err)
+	}
+
+	return sl, nil
+}
@@ -0,0 +1,64 @@
+package saledb
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/vitoraalmeida/service/business/core/sale"
+)
+
+// exprColumns mapeia os campos aceitos na expressão de filtro
+// (sale.FilterFields) para as colunas da tabela
+var exprColumns = map[string]string{
+	"sale_id":      "sale_id",
+	"user_id":      "user_id",
+	"total":        "total",
+	"date_created": "date_created",
+}
+
+// applyFilter adiciona na query a parte do WHERE com base nos campos não nulos
+// do filtro. Segue a mesma lógica de userdb.applyFilter
+func (s *Store) applyFilter(filter sale.QueryFilter, data map[string]interface{}, buf *bytes.Buffer) error {
+	var wc []string
+
+	if filter.ID != nil {
+		data["sale_id"] = *filter.ID
+		wc = append(wc, "sale_id = :sale_id")
+	}
+
+	if filter.UserID != nil {
+		data["user_id"] = *filter.UserID
+		wc = append(wc, "user_id = :user_id")
+	}
+
+	if filter.ProductID != nil {
+		data["product_id"] = *filter.ProductID
+		wc = append(wc, "EXISTS (SELECT 1 FROM sale_items si WHERE si.sale_id = sales.sale_id AND si.product_id = :product_id)")
+	}
+
+	if filter.StartCreatedDate != nil {
+		data["start_date_created"] = *filter.StartCreatedDate
+		wc = append(wc, "date_created >= :start_date_created")
+	}
+
+	if filter.EndCreatedDate != nil {
+		data["end_date_created"] = *filter.EndCreatedDate
+		wc = append(wc, "date_created <= :end_date_created")
+	}
+
+	if filter.Expression != nil {
+		clauses, err := filter.Expression.Clauses(exprColumns, data)
+		if err != nil {
+			return fmt.Errorf("expression: %w", err)
+		}
+		wc = append(wc, clauses...)
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+
+	return nil
+}
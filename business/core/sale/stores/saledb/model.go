@@ -0,0 +1,87 @@
+package saledb
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/core/sale"
+	"github.com/vitoraalmeida/service/business/data/money"
+)
+
+// dbSale representa a estrutura que precisamos para mover dados entre a
+// aplicação e a tabela sales
+type dbSale struct {
+	ID          uuid.UUID   `db:"sale_id"`
+	UserID      uuid.UUID   `db:"user_id"`
+	Total       money.Money `db:"total"`
+	DateCreated time.Time   `db:"date_created"`
+}
+
+// dbItem representa uma linha da tabela sale_items
+type dbItem struct {
+	SaleID    uuid.UUID   `db:"sale_id"`
+	ProductID uuid.UUID   `db:"product_id"`
+	Quantity  int         `db:"quantity"`
+	Price     money.Money `db:"price"`
+	Currency  string      `db:"currency"`
+}
+
+// converte uma Sale de domínio em dbSale para inserir dados no banco
+func toDBSale(sl sale.Sale) dbSale {
+	return dbSale{
+		ID:          sl.ID,
+		UserID:      sl.UserID,
+		Total:       sl.Total,
+		DateCreated: sl.DateCreated.UTC(),
+	}
+}
+
+// converte os itens de uma Sale de domínio em dbItem para inserir no banco
+func toDBItems(sl sale.Sale) []dbItem {
+	items := make([]dbItem, len(sl.Items))
+	for i, itm := range sl.Items {
+		items[i] = dbItem{
+			SaleID:    sl.ID,
+			ProductID: itm.ProductID,
+			Quantity:  itm.Quantity,
+			Price:     itm.Price,
+			Currency:  itm.Price.Currency(),
+		}
+	}
+	return items
+}
+
+// converte de dbSale e seus itens para Sale de domínio
+func toCoreSale(dbSl dbSale, dbItems []dbItem) sale.Sale {
+	items := make([]sale.Item, len(dbItems))
+	for i, dbItm := range dbItems {
+		items[i] = sale.Item{
+			ProductID: dbItm.ProductID,
+			Quantity:  dbItm.Quantity,
+			Price:     money.New(dbItm.Price.Minor(), dbItm.Currency),
+		}
+	}
+
+	return sale.Sale{
+		ID:          dbSl.ID,
+		UserID:      dbSl.UserID,
+		Items:       items,
+		Total:       dbSl.Total,
+		DateCreated: dbSl.DateCreated.In(time.Local),
+	}
+}
+
+// converte o slice de dbSale que vem do banco em slice de vendas de domínio,
+// agrupando os itens pelo ID da venda
+func toCoreSaleSlice(dbSls []dbSale, dbItems []dbItem) []sale.Sale {
+	itemsBySale := make(map[uuid.UUID][]dbItem, len(dbSls))
+	for _, dbItm := range dbItems {
+		itemsBySale[dbItm.SaleID] = append(itemsBySale[dbItm.SaleID], dbItm)
+	}
+
+	sls := make([]sale.Sale, len(dbSls))
+	for i, dbSl := range dbSls {
+		sls[i] = toCoreSale(dbSl, itemsBySale[dbSl.ID])
+	}
+	return sls
+}
@@ -0,0 +1,262 @@
+// Package saledb contém as funcionalidades de acesso ao banco relacionadas a
+// vendas.
+package saledb
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/vitoraalmeida/service/business/core/sale"
+	"github.com/vitoraalmeida/service/business/data/order"
+	database "github.com/vitoraalmeida/service/business/sys/database/pgx"
+	"github.com/vitoraalmeida/service/business/sys/database/pgx/dbarray"
+	"go.uber.org/zap"
+)
+
+// Store gerencia o conjunto de API que usamos para interagir com o banco de dados
+type Store struct {
+	log *zap.SugaredLogger
+	db  *sqlx.DB
+}
+
+// NewStore constrói a API de acesso aos dados de vendas
+func NewStore(log *zap.SugaredLogger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Create registra a venda, seus itens e dá baixa no estoque dos produtos numa
+// única transação, registrando a saída no histórico de estoque. Se algum
+// produto não tiver estoque suficiente nada é alterado e retorna
+// sale.ErrInsufficientStock
+func (s *Store) Create(ctx context.Context, sl sale.Sale) error {
+	const qSale = `
+	INSERT INTO sales
+		(sale_id, user_id, total, date_created)
+	VALUES
+		(:sale_id, :user_id, :total, :date_created)`
+
+	// a condição sobre quantity garante que o estoque nunca fica negativo,
+	// mesmo com vendas concorrentes do mesmo produto
+	const qStock = `
+	UPDATE
+		products
+	SET
+		quantity = quantity - CAST(:quantity AS INT),
+		sold = sold + CAST(:quantity AS INT),
+		revenue = revenue + CAST(:price AS NUMERIC) * CAST(:quantity AS INT),
+		date_updated = :date_updated
+	WHERE
+		product_id = :product_id AND quantity >= CAST(:quantity AS INT)
+	RETURNING
+		product_id`
+
+	const qItem = `
+	INSERT INTO sale_items
+		(sale_id, product_id, quantity, price, currency)
+	VALUES
+		(:sale_id, :product_id, :quantity, :price, :currency)`
+
+	const qMovement = `
+	INSERT INTO inventory_movements
+		(movement_id, product_id, user_id, kind, quantity, reason, date_created)
+	VALUES
+		(:movement_id, :product_id, :user_id, 'SALE', -CAST(:quantity AS INT), :reason, :date_created)`
+
+	f := func(tx *sqlx.Tx) error {
+		if err := database.NamedExecContext(ctx, s.log, tx, qSale, toDBSale(sl)); err != nil {
+			return fmt.Errorf("namedexeccontext: %w", err)
+		}
+
+		for _, dbItm := range toDBItems(sl) {
+			data := map[string]interface{}{
+				"product_id":   dbItm.ProductID,
+				"quantity":     dbItm.Quantity,
+				"price":        dbItm.Price,
+				"date_updated": sl.DateCreated.UTC(),
+			}
+
+			var updated struct {
+				ProductID uuid.UUID `db:"product_id"`
+			}
+			if err := database.NamedQueryStruct(ctx, s.log, tx, qStock, data, &updated); err != nil {
+				if errors.Is(err, database.ErrDBNotFound) {
+					return fmt.Errorf("productID[%s]: %w", dbItm.ProductID, sale.ErrInsufficientStock)
+				}
+				return fmt.Errorf("namedquerystruct: %w", err)
+			}
+
+			if err := database.NamedExecContext(ctx, s.log, tx, qItem, dbItm); err != nil {
+				return fmt.Errorf("namedexeccontext: %w", err)
+			}
+
+			mv := map[string]interface{}{
+				"movement_id":  uuid.New(),
+				"product_id":   dbItm.ProductID,
+				"user_id":      sl.UserID,
+				"quantity":     dbItm.Quantity,
+				"reason":       fmt.Sprintf("sale %s", sl.ID),
+				"date_created": sl.DateCreated.UTC(),
+			}
+			if err := database.NamedExecContext(ctx, s.log, tx, qMovement, mv); err != nil {
+				return fmt.Errorf("namedexeccontext: %w", err)
+			}
+		}
+
+		return nil
+	}
+
+	if err := database.WithinTran(ctx, s.log, s.db, f); err != nil {
+		return fmt.Errorf("withintran: %w", err)
+	}
+
+	return nil
+}
+
+// Query busca uma lista de vendas existentes no banco, com seus itens
+func (s *Store) Query(ctx context.Context, filter sale.QueryFilter, orderBy order.By, pageNumber int, rowsPerPage int) ([]sale.Sale, error) {
+	data := map[string]interface{}{
+		"offset":        (pageNumber - 1) * rowsPerPage,
+		"rows_per_page": rowsPerPage,
+	}
+
+	const q = `
+	SELECT
+		sale_id, user_id, total, date_created
+	FROM
+		sales`
+
+	buf := bytes.NewBufferString(q)
+	if err := s.applyFilter(filter, data, buf); err != nil {
+		return nil, err
+	}
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbSls []dbSale
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbSls); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	ids := make([]uuid.UUID, len(dbSls))
+	for i, dbSl := range dbSls {
+		ids[i] = dbSl.ID
+	}
+
+	dbItems, err := s.queryItems(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return toCoreSaleSlice(dbSls, dbItems), nil
+}
+
+// Count retorna o total de vendas no banco que atendem ao filtro
+func (s *Store) Count(ctx context.Context, filter sale.QueryFilter) (int, error) {
+	data := map[string]interface{}{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		sales`
+
+	buf := bytes.NewBufferString(q)
+	if err := s.applyFilter(filter, data, buf); err != nil {
+		return 0, err
+	}
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryByID busca a venda especificada com seus itens
+func (s *Store) QueryByID(ctx context.Context, saleID uuid.UUID) (sale.Sale, error) {
+	data := struct {
+		ID string `db:"sale_id"`
+	}{
+		ID: saleID.String(),
+	}
+
+	const q = `
+	SELECT
+		sale_id, user_id, total, date_created
+	FROM
+		sales
+	WHERE
+		sale_id = :sale_id`
+
+	var dbSl dbSale
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbSl); err != nil {
+		if errors.Is(err, database.ErrDBNotFound) {
+			return sale.Sale{}, fmt.Errorf("namedquerystruct: %w", sale.ErrNotFound)
+		}
+		return sale.Sale{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	dbItems, err := s.queryItems(ctx, []uuid.UUID{saleID})
+	if err != nil {
+		return sale.Sale{}, err
+	}
+
+	return toCoreSale(dbSl, dbItems), nil
+}
+
+// queryItems busca numa única consulta os itens das vendas especificadas
+func (s *Store) queryItems(ctx context.Context, saleIDs []uuid.UUID) ([]dbItem, error) {
+	if len(saleIDs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(saleIDs))
+	for i, saleID := range saleIDs {
+		ids[i] = saleID.String()
+	}
+
+	// possibilita passar um array para o sqlx
+	data := struct {
+		SaleID interface {
+			driver.Valuer
+			sql.Scanner
+		} `db:"sale_id"`
+	}{
+		SaleID: dbarray.Array(ids),
+	}
+
+	const q = `
+	SELECT
+		sale_id, product_id, quantity, price, currency
+	FROM
+		sale_items
+	WHERE
+		sale_id = ANY(:sale_id)
+	ORDER BY
+		sale_id, product_id`
+
+	var dbItems []dbItem
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbItems); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return dbItems, nil
+}
@@ -6,9 +6,22 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/data/expr"
 	"github.com/vitoraalmeida/service/business/sys/validate"
 )
 
+// FilterFields são os campos que podem ser usados na expressão de filtro e
+// seus tipos
+var FilterFields = expr.Fields{
+	"user_id":      expr.UUID,
+	"name":         expr.String,
+	"email":        expr.String,
+	"department":   expr.String,
+	"enabled":      expr.Bool,
+	"date_created": expr.Time,
+	"date_updated": expr.Time,
+}
+
 // QueryFilter agrupa os campos disponíveis pelos quais uma consulta pode ser filtrada
 type QueryFilter struct {
 	// utiliza ponteiros para dar a possibilidade de deixar um ou mais campos vazios (nil)
@@ -19,6 +32,9 @@ type QueryFilter struct {
 	Email            *mail.Address `validate:"omitempty"`       // pode não ter regra específica de validação, pois já estamos usando um tipo específico UUID
 	StartCreatedDate *time.Time    `validate:"omitempty"`
 	EndCreatedDate   *time.Time    `validate:"omitempty"`
+
+	// condições livres informadas no parâmetro filter
+	Expression expr.Expression
 }
 
 // Validate checa se o dado está no formato correto
@@ -55,3 +71,9 @@ func (qf *QueryFilter) WithEndCreatedDate(endDate time.Time) {
 	d := endDate.UTC()
 	qf.EndCreatedDate = &d
 }
+
+// WithExpression define a expressão de filtro que será combinada com os
+// demais campos
+func (qf *QueryFilter) WithExpression(exp expr.Expression) {
+	qf.Expression = exp
+}
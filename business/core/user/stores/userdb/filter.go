@@ -8,6 +8,18 @@ import (
 	"github.com/vitoraalmeida/service/business/core/user"
 )
 
+// exprColumns mapeia os campos aceitos na expressão de filtro (user.FilterFields)
+// para as colunas da tabela
+var exprColumns = map[string]string{
+	"user_id":      "user_id",
+	"name":         "name",
+	"email":        "email",
+	"department":   "department",
+	"enabled":      "enabled",
+	"date_created": "date_created",
+	"date_updated": "date_updated",
+}
+
 // applyFilter vai criar a pacela da query SELECT após o WHERE para que possamos
 // filtrar, selecionar apenas os campos que quisermos com base num objeto que vai
 // ser passado em que campos nulos não serão selecionados na query
@@ -40,9 +52,9 @@ User {
 */
 
 // buf = a query SQL que será gerada e modificada aqui
-func (s *Store) applyFilter(filter user.QueryFilter, data map[string]interface{}, buf *bytes.Buffer) {
-	var wc []string
-
+// wc = clausulas where adicionais que devem ser combinadas com o filtro, como a
+// posição de um cursor
+func (s *Store) applyFilter(filter user.QueryFilter, data map[string]interface{}, buf *bytes.Buffer, wc ...string) error {
 	if filter.ID != nil {
 		data["user_id"] = *filter.ID
 		// wc representa o conjunto de clausulas where que serão usadas (where clauses)
@@ -69,10 +81,22 @@ func (s *Store) applyFilter(filter user.QueryFilter, data map[string]interface{}
 		wc = append(wc, "date_created <= :end_date_created")
 	}
 
+	// as condições da expressão de filtro são convertidas em clausulas com
+	// parâmetros nomeados, da mesma forma que os campos acima
+	if filter.Expression != nil {
+		clauses, err := filter.Expression.Clauses(exprColumns, data)
+		if err != nil {
+			return fmt.Errorf("expression: %w", err)
+		}
+		wc = append(wc, clauses...)
+	}
+
 	if len(wc) > 0 {
 		// adicionamos o WHERE na query base
 		buf.WriteString(" WHERE ")
 		// adicionamos cada um dos campos que vamos selecionar
 		buf.WriteString(strings.Join(wc, " AND "))
 	}
+
+	return nil
 }
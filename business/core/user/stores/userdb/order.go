@@ -2,8 +2,11 @@ package userdb
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/vitoraalmeida/service/business/core/user"
+	"github.com/vitoraalmeida/service/business/data/cursor"
 	"github.com/vitoraalmeida/service/business/data/order"
 )
 
@@ -15,13 +18,91 @@ var orderByFields = map[string]string{
 	user.OrderByEnabled: "enabled",
 }
 
+// tipo de cada coluna de ordenação. O valor guardado no cursor é texto, então
+// precisa ser convertido para ser comparado com a coluna
+var orderByTypes = map[string]string{
+	user.OrderByID:      "UUID",
+	user.OrderByName:    "TEXT",
+	user.OrderByEmail:   "TEXT",
+	user.OrderByRoles:   "TEXT[]",
+	user.OrderByEnabled: "BOOLEAN",
+}
+
+// orderByKeys retorna os campos da ordenação terminando sempre na chave
+// primária, para que a ordem das linhas seja determinística e a paginação
+// estável mesmo quando vários usuários têm o mesmo valor num campo
+func orderByKeys(orderBy order.By) ([]order.Field, error) {
+	fields := make([]order.Field, 0, len(orderBy.Fields)+1)
+
+	for _, fld := range orderBy.Fields {
+		if _, exists := orderByFields[fld.Name]; !exists {
+			return nil, fmt.Errorf("field %q does not exist", fld.Name)
+		}
+
+		fields = append(fields, fld)
+
+		// nenhum campo depois da chave primária altera a ordem
+		if fld.Name == user.OrderByID {
+			return fields, nil
+		}
+	}
+
+	return append(fields, order.Field{Name: user.OrderByID, Direction: order.ASC}), nil
+}
+
 // adiciona na query que vai ser executada a parte da ordenação
 // caso seja necessário
 func orderByClause(orderBy order.By) (string, error) {
-	by, exists := orderByFields[orderBy.Field]
-	if !exists {
-		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	fields, err := orderByKeys(orderBy)
+	if err != nil {
+		return "", err
+	}
+
+	parts := make([]string, len(fields))
+	for i, fld := range fields {
+		parts[i] = orderByFields[fld.Name] + " " + fld.Direction
 	}
 
-	return " ORDER BY " + by + " " + orderBy.Direction, nil
+	return " ORDER BY " + strings.Join(parts, ", "), nil
+}
+
+// =============================================================================
+
+// cursorColumns descreve as colunas da ordenação para a consulta por cursor
+func cursorColumns(fields []order.Field) []cursor.Column {
+	columns := make([]cursor.Column, len(fields))
+	for i, fld := range fields {
+		columns[i] = cursor.Column{
+			Name:      orderByFields[fld.Name],
+			Type:      orderByTypes[fld.Name],
+			Direction: fld.Direction,
+		}
+	}
+
+	return columns
+}
+
+// cursorKey gera o cursor que aponta para a linha na ordenação
+func cursorKey(dbUsr dbUser, orderBy order.By, fields []order.Field) cursor.Cursor {
+	values := make([]string, len(fields))
+	for i, fld := range fields {
+		switch fld.Name {
+		case user.OrderByID:
+			values[i] = dbUsr.ID.String()
+		case user.OrderByName:
+			values[i] = dbUsr.Name
+		case user.OrderByEmail:
+			values[i] = dbUsr.Email
+		case user.OrderByRoles:
+			v, _ := dbUsr.Roles.Value()
+			values[i], _ = v.(string)
+		case user.OrderByEnabled:
+			values[i] = strconv.FormatBool(dbUsr.Enabled)
+		}
+	}
+
+	return cursor.Cursor{
+		Order:  orderBy.String(),
+		Values: values,
+	}
 }
@@ -9,10 +9,12 @@ import (
 	"errors"
 	"fmt"
 	"net/mail"
+	"slices"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/vitoraalmeida/service/business/core/user"
+	"github.com/vitoraalmeida/service/business/data/cursor"
 	"github.com/vitoraalmeida/service/business/data/order"
 	database "github.com/vitoraalmeida/service/business/sys/database/pgx"
 	"github.com/vitoraalmeida/service/business/sys/database/pgx/dbarray"
@@ -95,7 +97,9 @@ func (s *Store) Update(ctx context.Context, usr user.User) error {
 	return nil
 }
 
-// Delete removes a user from the database.
+// Delete remove um usuário do banco de dados. Usuários com vendas ou
+// movimentações de estoque registradas não podem ser removidos, para preservar
+// o histórico
 func (s *Store) Delete(ctx context.Context, usr user.User) error {
 	// a função de query espera um struct para saber sobre quais dados opera
 	// então construimos o struct aqui para possibilitar que o usuário da função
@@ -113,6 +117,9 @@ func (s *Store) Delete(ctx context.Context, usr user.User) error {
 		user_id = :user_id`
 
 	if err := database.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		if errors.Is(err, database.ErrDBForeignKey) {
+			return fmt.Errorf("namedexeccontext: %w", user.ErrInUse)
+		}
 		return fmt.Errorf("namedexeccontext: %w", err)
 	}
 
@@ -130,14 +137,16 @@ func (s *Store) Query(ctx context.Context, filter user.QueryFilter, orderBy orde
 
 	const q = `
 	SELECT
-		*
+		user_id, name, email, roles, password_hash, enabled, department, date_created, date_updated
 	FROM
 		users`
 
 	buf := bytes.NewBufferString(q)
 	// adiciona na query a parte do WHERE e suas clausulas conforme
 	// os campos do objeto user que não forem núlos
-	s.applyFilter(filter, data, buf)
+	if err := s.applyFilter(filter, data, buf); err != nil {
+		return nil, err
+	}
 
 	// gera a parte do ORDER BY (campo e direção [crescente/descrescente])
 	orderByClause, err := orderByClause(orderBy)
@@ -158,6 +167,61 @@ func (s *Store) Query(ctx context.Context, filter user.QueryFilter, orderBy orde
 	return toCoreUserSlice(dbUsrs), nil
 }
 
+// QueryByCursor busca uma página de usuários a partir da posição do cursor,
+// sem usar OFFSET. Retorna também os cursores das páginas vizinhas
+func (s *Store) QueryByCursor(ctx context.Context, filter user.QueryFilter, orderBy order.By, cur cursor.Cursor, rowsPerPage int) ([]user.User, cursor.Page, error) {
+	// buscamos uma linha a mais para saber se existe mais uma página
+	data := map[string]interface{}{
+		"rows_per_page": rowsPerPage + 1,
+	}
+
+	const q = `
+	SELECT
+		user_id, name, email, roles, password_hash, enabled, department, date_created, date_updated
+	FROM
+		users`
+
+	fields, err := orderByKeys(orderBy)
+	if err != nil {
+		return nil, cursor.Page{}, err
+	}
+	columns := cursorColumns(fields)
+
+	var wc []string
+	if !cur.IsZero() {
+		clause, err := cursor.Clause(columns, cur, data)
+		if err != nil {
+			return nil, cursor.Page{}, err
+		}
+		wc = append(wc, clause)
+	}
+
+	buf := bytes.NewBufferString(q)
+	if err := s.applyFilter(filter, data, buf, wc...); err != nil {
+		return nil, cursor.Page{}, err
+	}
+
+	buf.WriteString(cursor.OrderByClause(columns, cur))
+	buf.WriteString(" FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbUsrs []dbUser
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbUsrs); err != nil {
+		return nil, cursor.Page{}, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	// ao navegar para trás a consulta é feita em ordem invertida, então
+	// desfazemos a inversão antes de entregar as linhas
+	if cur.Direction == cursor.Prev {
+		slices.Reverse(dbUsrs)
+	}
+
+	dbUsrs, page := cursor.Paginate(dbUsrs, cur, rowsPerPage, func(dbUsr dbUser) cursor.Cursor {
+		return cursorKey(dbUsr, orderBy, fields)
+	})
+
+	return toCoreUserSlice(dbUsrs), page, nil
+}
+
 // Count retorna o total de usuário no banco
 func (s *Store) Count(ctx context.Context, filter user.QueryFilter) (int, error) {
 	data := map[string]interface{}{}
@@ -169,7 +233,9 @@ func (s *Store) Count(ctx context.Context, filter user.QueryFilter) (int, error)
 		users`
 
 	buf := bytes.NewBufferString(q)
-	s.applyFilter(filter, data, buf)
+	if err := s.applyFilter(filter, data, buf); err != nil {
+		return 0, err
+	}
 
 	var count struct {
 		Count int `db:"count"`
@@ -191,7 +257,7 @@ func (s *Store) QueryByID(ctx context.Context, userID uuid.UUID) (user.User, err
 
 	const q = `
 	SELECT
-		*
+		user_id, name, email, roles, password_hash, enabled, department, date_created, date_updated
 	FROM
 		users
 	WHERE 
@@ -228,7 +294,7 @@ func (s *Store) QueryByIDs(ctx context.Context, userIDs []uuid.UUID) ([]user.Use
 
 	const q = `
 	SELECT
-		*
+		user_id, name, email, roles, password_hash, enabled, department, date_created, date_updated
 	FROM
 		users
 	WHERE
@@ -255,7 +321,7 @@ func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (user.User
 
 	const q = `
 	SELECT
-		*
+		user_id, name, email, roles, password_hash, enabled, department, date_created, date_updated
 	FROM
 		users
 	WHERE
@@ -2,21 +2,23 @@ package user
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"net/mail"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/data/cursor"
 	"github.com/vitoraalmeida/service/business/data/order"
+	"github.com/vitoraalmeida/service/business/sys/errs"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // Conjunto de erros para operações CRUD
 var (
-	ErrNotFound              = errors.New("user not found")
-	ErrUniqueEmail           = errors.New("email is not unique")
-	ErrAuthenticationFailure = errors.New("authentication failed")
+	ErrNotFound              = errs.New(errs.UserNotFound, "user not found")
+	ErrUniqueEmail           = errs.New(errs.EmailTaken, "email is not unique")
+	ErrAuthenticationFailure = errs.New(errs.AuthenticationFailed, "authentication failed")
+	ErrInUse                 = errs.New(errs.UserInUse, "user has sales or inventory history")
 )
 
 // Abstrai qual é a implementação de fato que vai gerenciar a interção
@@ -26,6 +28,7 @@ type Storer interface {
 	Update(ctx context.Context, usr User) error
 	Delete(ctx context.Context, usr User) error
 	Query(ctx context.Context, filter QueryFilter, orderBy order.By, pageNumber int, rowsPerPage int) ([]User, error)
+	QueryByCursor(ctx context.Context, filter QueryFilter, orderBy order.By, cur cursor.Cursor, rowsPerPage int) ([]User, cursor.Page, error)
 	Count(ctx context.Context, filter QueryFilter) (int, error)
 	QueryByID(ctx context.Context, userID uuid.UUID) (User, error)
 	QueryByIDs(ctx context.Context, userID []uuid.UUID) ([]User, error)
@@ -134,6 +137,18 @@ func (c *Core) Query(ctx context.Context, filter QueryFilter, orderBy order.By,
 	return users, nil
 }
 
+// QueryByCursor entrega uma página de usuários a partir da posição de um cursor
+// e os cursores para as páginas vizinhas. Diferente de Query, o custo não
+// cresce conforme avançamos nas páginas
+func (c *Core) QueryByCursor(ctx context.Context, filter QueryFilter, orderBy order.By, cur cursor.Cursor, rowsPerPage int) ([]User, cursor.Page, error) {
+	users, page, err := c.storer.QueryByCursor(ctx, filter, orderBy, cur, rowsPerPage)
+	if err != nil {
+		return nil, cursor.Page{}, fmt.Errorf("querybycursor: %w", err)
+	}
+
+	return users, page, nil
+}
+
 // Count retorna o número total de usuaŕios no banco
 func (c *Core) Count(ctx context.Context, filter QueryFilter) (int, error) {
 	return c.storer.Count(ctx, filter)
@@ -0,0 +1,74 @@
+package report
+
+import "errors"
+
+// Tamanhos possíveis para os intervalos de tempo dos relatórios
+var (
+	BucketDay   = Bucket{"day"}
+	BucketWeek  = Bucket{"week"}
+	BucketMonth = Bucket{"month"}
+)
+
+// Conjunto dos tamanhos de intervalo
+var buckets = map[string]Bucket{
+	BucketDay.name:   BucketDay,
+	BucketWeek.name:  BucketWeek,
+	BucketMonth.name: BucketMonth,
+}
+
+// Bucket representa o tamanho do intervalo de tempo em que os dados de um
+// relatório são agrupados. O nome é o mesmo aceito pelo date_trunc do postgres
+type Bucket struct {
+	name string
+}
+
+// ParseBucket recebe um texto e converte para um tamanho de intervalo existente
+func ParseBucket(value string) (Bucket, error) {
+	bucket, exists := buckets[value]
+	if !exists {
+		return Bucket{}, errors.New("invalid bucket")
+	}
+
+	return bucket, nil
+}
+
+// MustParseBucket chama panic() caso ParseBucket retorne erro
+func MustParseBucket(value string) Bucket {
+	bucket, err := ParseBucket(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return bucket
+}
+
+// Name retorna o nome do intervalo
+func (b Bucket) Name() string {
+	return b.name
+}
+
+// Interval retorna a duração do intervalo no formato de INTERVAL do postgres
+func (b Bucket) Interval() string {
+	return "1 " + b.name
+}
+
+// UnmarshalText converte json para bucket, rejeitando tamanhos desconhecidos
+func (b *Bucket) UnmarshalText(data []byte) error {
+	bucket, err := ParseBucket(string(data))
+	if err != nil {
+		return err
+	}
+
+	b.name = bucket.name
+	return nil
+}
+
+// MarshalText converte bucket para json
+func (b Bucket) MarshalText() ([]byte, error) {
+	return []byte(b.name), nil
+}
+
+// Equal provê suporte para o pacote go-cmp e testing
+func (b Bucket) Equal(b2 Bucket) bool {
+	return b.name == b2.name
+}
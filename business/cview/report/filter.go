@@ -0,0 +1,45 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vitoraalmeida/service/business/sys/validate"
+)
+
+// MaxPeriodYears limita a duração do período dos relatórios. Junto com
+// MaxBuckets, impede que o banco gere e percorra uma série de datas enorme
+const MaxPeriodYears = 10
+
+// QueryFilter define o período dos relatórios, considerando a data de
+// cadastro dos produtos. O início é inclusivo e o fim exclusivo
+type QueryFilter struct {
+	StartCreatedDate time.Time `validate:"required"`
+	EndCreatedDate   time.Time `validate:"required"`
+}
+
+// NewQueryFilter constrói o filtro para o período informado
+func NewQueryFilter(start time.Time, end time.Time) QueryFilter {
+	return QueryFilter{
+		StartCreatedDate: start.UTC(),
+		EndCreatedDate:   end.UTC(),
+	}
+}
+
+// Validate checa se o dado está no formato correto
+func (qf *QueryFilter) Validate() error {
+	if err := validate.Check(qf); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	if !qf.EndCreatedDate.After(qf.StartCreatedDate) {
+		return validate.NewFieldsError("end_created_date", errors.New("end_created_date must be after start_created_date"))
+	}
+
+	if qf.EndCreatedDate.After(qf.StartCreatedDate.AddDate(MaxPeriodYears, 0, 0)) {
+		return validate.NewFieldsError("end_created_date", fmt.Errorf("period must not exceed %d years", MaxPeriodYears))
+	}
+
+	return nil
+}
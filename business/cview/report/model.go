@@ -0,0 +1,39 @@
+package report
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/data/money"
+)
+
+// Os valores dos relatórios estão sempre na moeda padrão, convertidos pelas
+// taxas de câmbio atuais
+
+// ProductsCreated representa os produtos cadastrados num intervalo de tempo
+type ProductsCreated struct {
+	Period     time.Time // início do intervalo
+	TotalCount int
+	TotalCost  money.Money
+}
+
+// DepartmentCost representa a distribuição do custo dos produtos cadastrados
+// pelos usuários de um departamento
+type DepartmentCost struct {
+	Department string // vazio para usuários sem departamento
+	TotalUsers int
+	TotalCount int
+	TotalCost  money.Money
+	MinCost    money.Money
+	AvgCost    money.Money
+	MaxCost    money.Money
+}
+
+// UserInventory representa o valor em estoque dos produtos de um usuário
+type UserInventory struct {
+	UserID         uuid.UUID
+	UserName       string
+	TotalCount     int // produtos cadastrados
+	TotalQuantity  int // unidades em estoque
+	InventoryValue money.Money
+}
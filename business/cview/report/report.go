@@ -0,0 +1,109 @@
+// Package report implementa os relatórios gerenciais. Assim como summary, é
+// uma view que une dados de usuários, produtos e taxas de câmbio
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vitoraalmeida/service/business/sys/validate"
+)
+
+// MaxBuckets limita a quantidade de intervalos de um relatório, para que um
+// período longo com intervalos pequenos não gere uma resposta enorme
+const MaxBuckets = 1000
+
+// ErrTooManyBuckets indica que o período tem mais intervalos que o permitido
+var ErrTooManyBuckets = fmt.Errorf("period exceeds %d buckets", MaxBuckets)
+
+type Storer interface {
+	QueryProductsCreated(ctx context.Context, filter QueryFilter, bucket Bucket) ([]ProductsCreated, error)
+	QueryDepartmentCost(ctx context.Context, filter QueryFilter) ([]DepartmentCost, error)
+	QueryTopUsersByInventory(ctx context.Context, filter QueryFilter, limit int) ([]UserInventory, error)
+}
+
+// =============================================================================
+
+type Core struct {
+	storer Storer
+}
+
+func NewCore(storer Storer) *Core {
+	return &Core{
+		storer: storer,
+	}
+}
+
+// QueryProductsCreated retorna a quantidade e o custo dos produtos cadastrados
+// em cada intervalo do período. Intervalos sem produtos também são retornados
+func (c *Core) QueryProductsCreated(ctx context.Context, filter QueryFilter, bucket Bucket) ([]ProductsCreated, error) {
+	if err := checkBuckets(filter, bucket); err != nil {
+		return nil, err
+	}
+
+	prcs, err := c.storer.QueryProductsCreated(ctx, filter, bucket)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return prcs, nil
+}
+
+// QueryDepartmentCost retorna a distribuição do custo dos produtos cadastrados
+// no período, agrupada pelo departamento de quem os cadastrou
+func (c *Core) QueryDepartmentCost(ctx context.Context, filter QueryFilter) ([]DepartmentCost, error) {
+	dpcs, err := c.storer.QueryDepartmentCost(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return dpcs, nil
+}
+
+// QueryTopUsersByInventory retorna os usuários com maior valor em estoque,
+// considerando os produtos cadastrados no período
+func (c *Core) QueryTopUsersByInventory(ctx context.Context, filter QueryFilter, limit int) ([]UserInventory, error) {
+	usis, err := c.storer.QueryTopUsersByInventory(ctx, filter, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return usis, nil
+}
+
+// =============================================================================
+
+// checkBuckets verifica se o período cabe em MaxBuckets intervalos. A
+// contagem começa no início do intervalo que contém StartCreatedDate, como
+// faz o date_trunc usado na query
+func checkBuckets(filter QueryFilter, bucket Bucket) error {
+	start := filter.StartCreatedDate.UTC()
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+
+	var months, days int
+	switch bucket {
+	case BucketDay:
+		days = 1
+	case BucketWeek:
+		days = 7
+		// date_trunc('week') usa semanas ISO, que começam na segunda-feira
+		start = start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+	case BucketMonth:
+		months = 1
+		start = start.AddDate(0, 0, 1-start.Day())
+	default:
+		return validate.NewFieldsError("bucket", errors.New("invalid bucket"))
+	}
+
+	t := start
+	for i := 0; t.Before(filter.EndCreatedDate); i++ {
+		if i == MaxBuckets {
+			return validate.NewFieldsError("bucket", ErrTooManyBuckets)
+		}
+		t = t.AddDate(0, months, days)
+	}
+
+	return nil
+}
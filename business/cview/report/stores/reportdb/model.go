@@ -0,0 +1,78 @@
+package reportdb
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/cview/report"
+	"github.com/vitoraalmeida/service/business/data/money"
+)
+
+// dbProductsCreated representa uma linha do relatório de produtos cadastrados
+type dbProductsCreated struct {
+	Period     time.Time   `db:"period"`
+	TotalCount int         `db:"total_count"`
+	TotalCost  money.Money `db:"total_cost"`
+}
+
+func toCoreProductsCreatedSlice(dbPrcs []dbProductsCreated) []report.ProductsCreated {
+	prcs := make([]report.ProductsCreated, len(dbPrcs))
+	for i, dbPrc := range dbPrcs {
+		prcs[i] = report.ProductsCreated{
+			Period:     dbPrc.Period.In(time.UTC),
+			TotalCount: dbPrc.TotalCount,
+			TotalCost:  dbPrc.TotalCost,
+		}
+	}
+	return prcs
+}
+
+// dbDepartmentCost representa uma linha do relatório de custo por departamento
+type dbDepartmentCost struct {
+	Department string      `db:"department"`
+	TotalUsers int         `db:"total_users"`
+	TotalCount int         `db:"total_count"`
+	TotalCost  money.Money `db:"total_cost"`
+	MinCost    money.Money `db:"min_cost"`
+	AvgCost    money.Money `db:"avg_cost"`
+	MaxCost    money.Money `db:"max_cost"`
+}
+
+func toCoreDepartmentCostSlice(dbDpcs []dbDepartmentCost) []report.DepartmentCost {
+	dpcs := make([]report.DepartmentCost, len(dbDpcs))
+	for i, dbDpc := range dbDpcs {
+		dpcs[i] = report.DepartmentCost{
+			Department: dbDpc.Department,
+			TotalUsers: dbDpc.TotalUsers,
+			TotalCount: dbDpc.TotalCount,
+			TotalCost:  dbDpc.TotalCost,
+			MinCost:    dbDpc.MinCost,
+			AvgCost:    dbDpc.AvgCost,
+			MaxCost:    dbDpc.MaxCost,
+		}
+	}
+	return dpcs
+}
+
+// dbUserInventory representa uma linha do relatório de valor em estoque
+type dbUserInventory struct {
+	UserID         uuid.UUID   `db:"user_id"`
+	UserName       string      `db:"user_name"`
+	TotalCount     int         `db:"total_count"`
+	TotalQuantity  int         `db:"total_quantity"`
+	InventoryValue money.Money `db:"inventory_value"`
+}
+
+func toCoreUserInventorySlice(dbUsis []dbUserInventory) []report.UserInventory {
+	usis := make([]report.UserInventory, len(dbUsis))
+	for i, dbUsi := range dbUsis {
+		usis[i] = report.UserInventory{
+			UserID:         dbUsi.UserID,
+			UserName:       dbUsi.UserName,
+			TotalCount:     dbUsi.TotalCount,
+			TotalQuantity:  dbUsi.TotalQuantity,
+			InventoryValue: dbUsi.InventoryValue,
+		}
+	}
+	return usis
+}
@@ -0,0 +1,147 @@
+// Package reportdb contém as consultas dos relatórios gerenciais.
+package reportdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vitoraalmeida/service/business/cview/report"
+	database "github.com/vitoraalmeida/service/business/sys/database/pgx"
+	"go.uber.org/zap"
+)
+
+// Store gerencia o conjunto de API que usamos para interagir com o banco de dados
+type Store struct {
+	log *zap.SugaredLogger
+	db  *sqlx.DB
+}
+
+// NewStore constrói a API de acesso aos relatórios
+func NewStore(log *zap.SugaredLogger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// QueryProductsCreated agrupa os produtos cadastrados no período por
+// intervalo. generate_series cria todos os intervalos do período, para que
+// os intervalos sem produtos apareçam zerados
+func (s *Store) QueryProductsCreated(ctx context.Context, filter report.QueryFilter, bucket report.Bucket) ([]report.ProductsCreated, error) {
+	data := map[string]interface{}{
+		"bucket":             bucket.Name(),
+		"interval":           bucket.Interval(),
+		"start_created_date": filter.StartCreatedDate,
+		"end_created_date":   filter.EndCreatedDate,
+	}
+
+	const q = `
+	WITH periods AS (
+		SELECT
+			generate_series(
+				date_trunc(:bucket, CAST(:start_created_date AS TIMESTAMP)),
+				CAST(:end_created_date AS TIMESTAMP) - INTERVAL '1 microsecond',
+				CAST(:interval AS INTERVAL)
+			) AS period
+	)
+	SELECT
+		pe.period,
+		count(p.product_id) AS total_count,
+		coalesce(sum(p.cost / r.rate), 0) AS total_cost
+	FROM
+		periods AS pe
+	LEFT JOIN
+		products AS p ON date_trunc(:bucket, p.date_created) = pe.period
+			AND p.date_created >= :start_created_date
+			AND p.date_created < :end_created_date
+	LEFT JOIN
+		exchange_rates AS r ON r.currency = p.currency
+	GROUP BY
+		pe.period
+	ORDER BY
+		pe.period`
+
+	var dbPrcs []dbProductsCreated
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbPrcs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreProductsCreatedSlice(dbPrcs), nil
+}
+
+// QueryDepartmentCost agrupa o custo dos produtos cadastrados no período pelo
+// departamento do usuário que os cadastrou
+func (s *Store) QueryDepartmentCost(ctx context.Context, filter report.QueryFilter) ([]report.DepartmentCost, error) {
+	data := map[string]interface{}{
+		"start_created_date": filter.StartCreatedDate,
+		"end_created_date":   filter.EndCreatedDate,
+	}
+
+	const q = `
+	SELECT
+		coalesce(u.department, '') AS department,
+		count(DISTINCT u.user_id) AS total_users,
+		count(p.product_id) AS total_count,
+		sum(p.cost / r.rate) AS total_cost,
+		min(p.cost / r.rate) AS min_cost,
+		avg(p.cost / r.rate) AS avg_cost,
+		max(p.cost / r.rate) AS max_cost
+	FROM
+		products AS p
+	JOIN
+		users AS u ON u.user_id = p.user_id
+	JOIN
+		exchange_rates AS r ON r.currency = p.currency
+	WHERE
+		p.date_created >= :start_created_date AND p.date_created < :end_created_date
+	GROUP BY
+		coalesce(u.department, '')
+	ORDER BY
+		total_cost DESC, department`
+
+	var dbDpcs []dbDepartmentCost
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbDpcs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreDepartmentCostSlice(dbDpcs), nil
+}
+
+// QueryTopUsersByInventory soma o valor em estoque (custo vezes quantidade)
+// dos produtos cadastrados no período por usuário, retornando os maiores
+func (s *Store) QueryTopUsersByInventory(ctx context.Context, filter report.QueryFilter, limit int) ([]report.UserInventory, error) {
+	data := map[string]interface{}{
+		"start_created_date": filter.StartCreatedDate,
+		"end_created_date":   filter.EndCreatedDate,
+		"limit":              limit,
+	}
+
+	const q = `
+	SELECT
+		u.user_id,
+		u.name AS user_name,
+		count(p.product_id) AS total_count,
+		sum(p.quantity) AS total_quantity,
+		sum(p.cost * p.quantity / r.rate) AS inventory_value
+	FROM
+		products AS p
+	JOIN
+		users AS u ON u.user_id = p.user_id
+	JOIN
+		exchange_rates AS r ON r.currency = p.currency
+	WHERE
+		p.date_created >= :start_created_date AND p.date_created < :end_created_date
+	GROUP BY
+		u.user_id, u.name
+	ORDER BY
+		inventory_value DESC, u.user_id
+	FETCH NEXT :limit ROWS ONLY`
+
+	var dbUsis []dbUserInventory
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbUsis); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreUserInventorySlice(dbUsis), nil
+}
@@ -0,0 +1,49 @@
+package search
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/sys/validate"
+)
+
+// QueryFilter agrupa os campos usados numa busca. Text é obrigatório e os
+// demais restringem quais registros podem aparecer no resultado
+type QueryFilter struct {
+	Text string  `validate:"required,min=2"`
+	Kind *string `validate:"omitempty,oneof=user product"`
+
+	// UserScope limita os usuários retornados ao próprio chamador. Quando
+	// ExcludeUsers é verdadeiro nenhum usuário é retornado. Ambos são
+	// definidos pela camada de aplicação com base nas permissões do chamador
+	UserScope    *uuid.UUID
+	ExcludeUsers bool
+}
+
+// Validate checa se o dado está no formato correto
+func (qf *QueryFilter) Validate() error {
+	if err := validate.Check(qf); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+	return nil
+}
+
+// WithText define o texto a ser buscado
+func (qf *QueryFilter) WithText(text string) {
+	qf.Text = text
+}
+
+// WithKind restringe a busca a um único tipo de registro
+func (qf *QueryFilter) WithKind(kind string) {
+	qf.Kind = &kind
+}
+
+// WithUserScope restringe os usuários retornados ao usuário informado
+func (qf *QueryFilter) WithUserScope(userID uuid.UUID) {
+	qf.UserScope = &userID
+}
+
+// WithoutUsers remove os usuários do resultado
+func (qf *QueryFilter) WithoutUsers() {
+	qf.ExcludeUsers = true
+}
@@ -0,0 +1,19 @@
+package search
+
+import "github.com/google/uuid"
+
+// Tipos de resultado retornados pela busca
+const (
+	KindUser    = "user"
+	KindProduct = "product"
+)
+
+// Result representa um registro encontrado pela busca textual, vindo de
+// users ou de products
+type Result struct {
+	Kind      string    // user ou product
+	ID        uuid.UUID // user_id ou product_id, conforme Kind
+	Title     string    // nome do usuário ou do produto
+	Highlight string    // trecho em HTML escapado, com os termos encontrados entre <b> e </b>
+	Rank      float64   // relevância calculada pelo postgres
+}
@@ -0,0 +1,40 @@
+// Package search implementa a busca textual sobre usuários e produtos.
+// Assim como summary, é uma view que atravessa mais de um domínio
+package search
+
+import (
+	"context"
+	"fmt"
+)
+
+type Storer interface {
+	Query(ctx context.Context, filter QueryFilter, pageNumber int, rowsPerPage int) ([]Result, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+}
+
+// =============================================================================
+
+type Core struct {
+	storer Storer
+}
+
+func NewCore(storer Storer) *Core {
+	return &Core{
+		storer: storer,
+	}
+}
+
+// Query retorna os registros encontrados, do mais relevante para o menos
+// relevante
+func (c *Core) Query(ctx context.Context, filter QueryFilter, pageNumber int, rowsPerPage int) ([]Result, error) {
+	results, err := c.storer.Query(ctx, filter, pageNumber, rowsPerPage)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return results, nil
+}
+
+func (c *Core) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	return c.storer.Count(ctx, filter)
+}
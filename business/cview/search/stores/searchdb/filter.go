@@ -0,0 +1,67 @@
+package searchdb
+
+import (
+	"strings"
+
+	"github.com/vitoraalmeida/service/business/cview/search"
+)
+
+// cada tabela pesquisada contribui com um SELECT para o UNION ALL. O campo
+// document é o texto usado para gerar o destaque dos termos encontrados
+const (
+	usersSelect = `
+	SELECT
+		'user' AS kind, u.user_id AS id, u.name AS title,
+		u.name || ' ' || u.email || ' ' || coalesce(u.department, '') AS document,
+		ts_rank(u.search, query.q) AS rank
+	FROM
+		users u, query
+	WHERE
+		u.search @@ query.q`
+
+	productsSelect = `
+	SELECT
+		'product' AS kind, p.product_id AS id, p.name AS title,
+		p.name AS document,
+		ts_rank(p.search, query.q) AS rank
+	FROM
+		products p, query
+	WHERE
+		p.search @@ query.q`
+)
+
+// applyFilter monta a parte da consulta que une as tabelas pesquisadas com
+// base no filtro. Retorna vazio quando o filtro não permite nenhuma tabela,
+// por exemplo ao buscar apenas usuários sem poder ver nenhum
+func (s *Store) applyFilter(filter search.QueryFilter, data map[string]interface{}) string {
+	data["q"] = filter.Text
+
+	var parts []string
+
+	if wants(filter, search.KindUser) && !filter.ExcludeUsers {
+		q := usersSelect
+		if filter.UserScope != nil {
+			data["user_scope"] = *filter.UserScope
+			q += " AND u.user_id = :user_scope"
+		}
+		parts = append(parts, q)
+	}
+
+	if wants(filter, search.KindProduct) {
+		parts = append(parts, productsSelect)
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return `
+	WITH query AS (
+		SELECT websearch_to_tsquery('simple', :q) AS q
+	)` + "\n\tSELECT kind, id, title, document, rank FROM (" + strings.Join(parts, "\n\tUNION ALL") + "\n\t) AS results"
+}
+
+// wants informa se o filtro aceita registros do tipo informado
+func wants(filter search.QueryFilter, kind string) bool {
+	return filter.Kind == nil || *filter.Kind == kind
+}
@@ -0,0 +1,48 @@
+package searchdb
+
+import (
+	"html"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/cview/search"
+)
+
+// dbResult representa uma linha retornada pela busca
+type dbResult struct {
+	Kind      string    `db:"kind"`
+	ID        uuid.UUID `db:"id"`
+	Title     string    `db:"title"`
+	Highlight string    `db:"highlight"`
+	Rank      float64   `db:"rank"`
+}
+
+// converte de dbResult para Result de domínio
+func toCoreResult(dbRes dbResult) search.Result {
+	return search.Result{
+		Kind:      dbRes.Kind,
+		ID:        dbRes.ID,
+		Title:     dbRes.Title,
+		Highlight: highlight(dbRes.Highlight),
+		Rank:      dbRes.Rank,
+	}
+}
+
+// converte o slice de dbResult que vem do banco em slice de Result de domínio
+func toCoreResultSlice(dbRess []dbResult) []search.Result {
+	ress := make([]search.Result, len(dbRess))
+	for i, dbRes := range dbRess {
+		ress[i] = toCoreResult(dbRes)
+	}
+	return ress
+}
+
+// marcadores usados pelo ts_headline no lugar das tags, para que o documento
+// possa ser escapado sem perder os destaques
+var highlighter = strings.NewReplacer("\x01", "<b>", "\x02", "</b>")
+
+// highlight escapa o trecho retornado pelo banco e troca os marcadores pelas
+// tags de destaque
+func highlight(s string) string {
+	return highlighter.Replace(html.EscapeString(s))
+}
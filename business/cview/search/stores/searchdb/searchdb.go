@@ -0,0 +1,92 @@
+// Package searchdb contém as consultas de busca textual sobre users e products.
+package searchdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vitoraalmeida/service/business/cview/search"
+	database "github.com/vitoraalmeida/service/business/sys/database/pgx"
+	"go.uber.org/zap"
+)
+
+// Store gerencia o conjunto de API que usamos para interagir com o banco de dados
+type Store struct {
+	log *zap.SugaredLogger
+	db  *sqlx.DB
+}
+
+// NewStore constrói a API de acesso à busca
+func NewStore(log *zap.SugaredLogger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Query busca os registros que correspondem ao texto do filtro, ordenados
+// por relevância. O destaque dos termos é gerado só para a página retornada,
+// já que ts_headline é custoso. Os termos são marcados com caracteres de
+// controle, removidos antes do documento, e só trocados por <b> depois que o
+// texto é escapado, já que nomes e descrições vêm dos usuários
+func (s *Store) Query(ctx context.Context, filter search.QueryFilter, pageNumber int, rowsPerPage int) ([]search.Result, error) {
+	data := map[string]interface{}{
+		"offset":        (pageNumber - 1) * rowsPerPage,
+		"rows_per_page": rowsPerPage,
+	}
+
+	results := s.applyFilter(filter, data)
+	if results == "" {
+		return []search.Result{}, nil
+	}
+
+	q := results + `
+	ORDER BY rank DESC, kind, id
+	OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY`
+
+	q = fmt.Sprintf(`
+	SELECT
+		page.kind, page.id, page.title, page.rank,
+		ts_headline(
+			'simple',
+			translate(page.document, chr(1) || chr(2), ''),
+			websearch_to_tsquery('simple', :q),
+			'StartSel=' || chr(1) || ', StopSel=' || chr(2)
+		) AS highlight
+	FROM (%s
+	) AS page
+	ORDER BY page.rank DESC, page.kind, page.id`, q)
+
+	var dbRess []dbResult
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbRess); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreResultSlice(dbRess), nil
+}
+
+// Count retorna o total de registros que correspondem ao texto do filtro
+func (s *Store) Count(ctx context.Context, filter search.QueryFilter) (int, error) {
+	data := map[string]interface{}{}
+
+	results := s.applyFilter(filter, data)
+	if results == "" {
+		return 0, nil
+	}
+
+	q := fmt.Sprintf(`
+	SELECT
+		count(1)
+	FROM (%s
+	) AS page`, results)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, q, data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count, nil
+}
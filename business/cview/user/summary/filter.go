@@ -4,9 +4,19 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/data/expr"
 	"github.com/vitoraalmeida/service/business/sys/validate"
 )
 
+// FilterFields são os campos que podem ser usados na expressão de filtro e
+// seus tipos
+var FilterFields = expr.Fields{
+	"user_id":     expr.UUID,
+	"user_name":   expr.String,
+	"total_count": expr.Int,
+	"total_cost":  expr.Float,
+}
+
 // QueryFilter agrupa os campos disponíveis pelos quais uma consulta pode ser filtrada. É passado por query params numa url
 type QueryFilter struct {
 	// utiliza ponteiros para dar a possibilidade de deixar um ou mais campos vazios (nil)
@@ -14,6 +24,9 @@ type QueryFilter struct {
 	// que não forem nulos
 	UserID   *uuid.UUID `validate:"omitempty,uuid4"`
 	UserName *string    `validate:"omitempty,min=3"`
+
+	// condições livres informadas no parâmetro filter
+	Expression expr.Expression
 }
 
 // Validate checks the data in the model is considered clean.
@@ -34,3 +47,9 @@ func (qf *QueryFilter) WithUserID(userID uuid.UUID) {
 func (qf *QueryFilter) WithUserName(userName string) {
 	qf.UserName = &userName
 }
+
+// WithExpression define a expressão de filtro que será combinada com os
+// demais campos
+func (qf *QueryFilter) WithExpression(exp expr.Expression) {
+	qf.Expression = exp
+}
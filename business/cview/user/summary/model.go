@@ -1,6 +1,9 @@
 package summary
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/data/money"
+)
 
 // Summary representa informação sobre um usuário e seus produtos
 // Foi gerado para que pudéssemos atender a necessidade fazer o
@@ -8,7 +11,7 @@ import "github.com/google/uuid"
 // os registraram
 type Summary struct {
 	UserID     uuid.UUID
-	UserName   string  // vem de user
-	TotalCount int     // vem de products
-	TotalCost  float64 // vem de Products
+	UserName   string      // vem de user
+	TotalCount int         // vem de products
+	TotalCost  money.Money // vem de Products
 }
@@ -0,0 +1,120 @@
+package summary
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Refresher é implementado pelos stores que mantêm uma cópia materializada
+// do resumo, que precisa ser recalculada periodicamente
+type Refresher interface {
+	Refresh(ctx context.Context) error
+}
+
+// RefreshStatus descreve o estado da atualização periódica do resumo
+type RefreshStatus struct {
+	Running       bool          // o worker está em execução
+	Refreshing    bool          // há uma atualização em andamento
+	Interval      time.Duration // intervalo entre as atualizações
+	Refreshes     int           // atualizações concluídas com sucesso
+	Failures      int           // atualizações que falharam
+	LastRefresh   time.Time     // fim da última atualização bem sucedida
+	LastDuration  time.Duration // duração da última atualização bem sucedida
+	LastError     string        // erro da última atualização que falhou
+	LastErrorTime time.Time
+}
+
+// RefreshWorker atualiza o resumo materializado a cada intervalo, em segundo
+// plano, e mantém o estado das atualizações para os endpoints de debug
+type RefreshWorker struct {
+	log       *zap.SugaredLogger
+	refresher Refresher
+	interval  time.Duration
+
+	mu     sync.RWMutex
+	status RefreshStatus
+}
+
+// NewRefreshWorker constrói o worker que atualiza o resumo a cada interval
+func NewRefreshWorker(log *zap.SugaredLogger, refresher Refresher, interval time.Duration) *RefreshWorker {
+	return &RefreshWorker{
+		log:       log,
+		refresher: refresher,
+		interval:  interval,
+		status: RefreshStatus{
+			Interval: interval,
+		},
+	}
+}
+
+// Run atualiza o resumo imediatamente e depois a cada intervalo, até que o
+// contexto seja cancelado. Deve ser executado numa goroutine própria. Como
+// uma atualização só começa depois que a anterior termina, elas nunca se
+// sobrepõem
+func (w *RefreshWorker) Run(ctx context.Context) {
+	w.setRunning(true)
+	defer w.setRunning(false)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Status retorna uma cópia do estado atual das atualizações
+func (w *RefreshWorker) Status() RefreshStatus {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.status
+}
+
+// refresh executa uma atualização, limitada à duração de um intervalo
+func (w *RefreshWorker) refresh(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, w.interval)
+	defer cancel()
+
+	w.mu.Lock()
+	w.status.Refreshing = true
+	w.mu.Unlock()
+
+	start := time.Now()
+	err := w.refresher.Refresh(ctx)
+	duration := time.Since(start)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.status.Refreshing = false
+
+	if err != nil {
+		w.status.Failures++
+		w.status.LastError = err.Error()
+		w.status.LastErrorTime = time.Now()
+		w.log.Errorw("summary refresh", "status", "failed", "duration", duration, "ERROR", err)
+		return
+	}
+
+	w.status.Refreshes++
+	w.status.LastRefresh = time.Now()
+	w.status.LastDuration = duration
+	w.log.Infow("summary refresh", "status", "completed", "duration", duration)
+}
+
+func (w *RefreshWorker) setRunning(running bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.status.Running = running
+}
@@ -0,0 +1,49 @@
+package summarydb
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/vitoraalmeida/service/business/cview/user/summary"
+)
+
+// exprColumns mapeia os campos aceitos na expressão de filtro
+// (summary.FilterFields) para as colunas da view
+var exprColumns = map[string]string{
+	"user_id":     "user_id",
+	"user_name":   "user_name",
+	"total_count": "total_count",
+	"total_cost":  "total_cost",
+}
+
+// applyFilter adiciona na query a parte do WHERE com base nos campos não nulos
+// do filtro. Segue a mesma lógica de userdb.applyFilter
+func (s *Store) applyFilter(filter summary.QueryFilter, data map[string]interface{}, buf *bytes.Buffer) error {
+	var wc []string
+
+	if filter.UserID != nil {
+		data["user_id"] = *filter.UserID
+		wc = append(wc, "user_id = :user_id")
+	}
+
+	if filter.UserName != nil {
+		data["user_name"] = fmt.Sprintf("%%%s%%", *filter.UserName)
+		wc = append(wc, "user_name LIKE :user_name")
+	}
+
+	if filter.Expression != nil {
+		clauses, err := filter.Expression.Clauses(exprColumns, data)
+		if err != nil {
+			return fmt.Errorf("expression: %w", err)
+		}
+		wc = append(wc, clauses...)
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package summarydb
+
+import (
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/cview/user/summary"
+	"github.com/vitoraalmeida/service/business/data/money"
+)
+
+// dbSummary representa uma linha da view user_summary
+type dbSummary struct {
+	UserID     uuid.UUID   `db:"user_id"`
+	UserName   string      `db:"user_name"`
+	TotalCount int         `db:"total_count"`
+	TotalCost  money.Money `db:"total_cost"`
+}
+
+// converte de dbSummary para Summary de domínio
+func toCoreSummary(dbSmm dbSummary) summary.Summary {
+	return summary.Summary{
+		UserID:     dbSmm.UserID,
+		UserName:   dbSmm.UserName,
+		TotalCount: dbSmm.TotalCount,
+		TotalCost:  dbSmm.TotalCost,
+	}
+}
+
+// converte o slice de dbSummary que vem do banco em slice de Summary de domínio
+func toCoreSummarySlice(dbSmms []dbSummary) []summary.Summary {
+	smms := make([]summary.Summary, len(dbSmms))
+	for i, dbSmm := range dbSmms {
+		smms[i] = toCoreSummary(dbSmm)
+	}
+	return smms
+}
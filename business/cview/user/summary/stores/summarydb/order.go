@@ -0,0 +1,38 @@
+package summarydb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vitoraalmeida/service/business/cview/user/summary"
+	"github.com/vitoraalmeida/service/business/data/order"
+)
+
+var orderByFields = map[string]string{
+	summary.OrderByUserID:   "user_id",
+	summary.OrderByUserName: "user_name",
+}
+
+// adiciona na query que vai ser executada a parte da ordenação, terminando
+// sempre em user_id, que identifica cada linha da view
+func orderByClause(orderBy order.By) (string, error) {
+	parts := make([]string, 0, len(orderBy.Fields)+1)
+
+	for _, fld := range orderBy.Fields {
+		by, exists := orderByFields[fld.Name]
+		if !exists {
+			return "", fmt.Errorf("field %q does not exist", fld.Name)
+		}
+
+		parts = append(parts, by+" "+fld.Direction)
+
+		// nenhum campo depois da chave altera a ordem
+		if fld.Name == summary.OrderByUserID {
+			return " ORDER BY " + strings.Join(parts, ", "), nil
+		}
+	}
+
+	parts = append(parts, "user_id "+order.ASC)
+
+	return " ORDER BY " + strings.Join(parts, ", "), nil
+}
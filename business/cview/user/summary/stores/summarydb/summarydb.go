@@ -0,0 +1,156 @@
+// Package summarydb contém as consultas da view user_summary.
+package summarydb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vitoraalmeida/service/business/cview/user/summary"
+	"github.com/vitoraalmeida/service/business/data/order"
+	database "github.com/vitoraalmeida/service/business/sys/database/pgx"
+	"go.uber.org/zap"
+)
+
+// views de onde o resumo pode ser lido
+const (
+	viewLive         = "user_summary"     // calculada a cada consulta
+	viewMaterialized = "user_summary_mat" // cópia atualizada por Refresh
+)
+
+// Store gerencia o conjunto de API que usamos para interagir com o banco de dados
+type Store struct {
+	log  *zap.SugaredLogger
+	db   *sqlx.DB
+	view string
+}
+
+// NewStore constrói a API de acesso à view, calculada a cada consulta
+func NewStore(log *zap.SugaredLogger, db *sqlx.DB) *Store {
+	return &Store{
+		log:  log,
+		db:   db,
+		view: viewLive,
+	}
+}
+
+// NewMaterializedStore constrói a API de acesso à cópia materializada da
+// view. As consultas ficam mais rápidas, mas os dados só mudam quando
+// Refresh é chamado
+func NewMaterializedStore(log *zap.SugaredLogger, db *sqlx.DB) *Store {
+	return &Store{
+		log:  log,
+		db:   db,
+		view: viewMaterialized,
+	}
+}
+
+// Refresh recalcula a cópia materializada da view. CONCURRENTLY permite que
+// a cópia continue sendo consultada durante o processo. Na view calculada a
+// cada consulta não há o que fazer
+func (s *Store) Refresh(ctx context.Context) error {
+	if s.view != viewMaterialized {
+		return nil
+	}
+
+	const q = `REFRESH MATERIALIZED VIEW CONCURRENTLY user_summary_mat`
+
+	if err := database.ExecContext(ctx, s.log, s.db, q); err != nil {
+		return fmt.Errorf("execcontext: %w", err)
+	}
+
+	return nil
+}
+
+// Query busca uma lista de resumos de usuários
+func (s *Store) Query(ctx context.Context, filter summary.QueryFilter, orderBy order.By, pageNumber int, rowsPerPage int) ([]summary.Summary, error) {
+	data := map[string]interface{}{
+		"offset":        (pageNumber - 1) * rowsPerPage,
+		"rows_per_page": rowsPerPage,
+	}
+
+	const q = `
+	SELECT
+		user_id, user_name, total_count, total_cost
+	FROM
+		`
+
+	buf := bytes.NewBufferString(q + s.view)
+	if err := s.applyFilter(filter, data, buf); err != nil {
+		return nil, err
+	}
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbSmms []dbSummary
+	if err := database.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbSmms); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreSummarySlice(dbSmms), nil
+}
+
+// QueryEach busca os resumos que atendem ao filtro, entregando um de cada vez
+// para fn conforme são lidos do banco
+func (s *Store) QueryEach(ctx context.Context, filter summary.QueryFilter, orderBy order.By, fn func(summary.Summary) error) error {
+	data := map[string]interface{}{}
+
+	const q = `
+	SELECT
+		user_id, user_name, total_count, total_cost
+	FROM
+		`
+
+	buf := bytes.NewBufferString(q + s.view)
+	if err := s.applyFilter(filter, data, buf); err != nil {
+		return err
+	}
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return err
+	}
+	buf.WriteString(orderByClause)
+
+	f := func(dbSmm dbSummary) error {
+		return fn(toCoreSummary(dbSmm))
+	}
+
+	if err := database.NamedQueryEach(ctx, s.log, s.db, buf.String(), data, f); err != nil {
+		return fmt.Errorf("namedqueryeach: %w", err)
+	}
+
+	return nil
+}
+
+// Count retorna o total de linhas da view
+func (s *Store) Count(ctx context.Context, filter summary.QueryFilter) (int, error) {
+	data := map[string]interface{}{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		`
+
+	buf := bytes.NewBufferString(q + s.view)
+	if err := s.applyFilter(filter, data, buf); err != nil {
+		return 0, err
+	}
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := database.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count, nil
+}
@@ -12,6 +12,7 @@ import (
 type Storer interface {
 	Query(ctx context.Context, filter QueryFilter, orderBy order.By, pageNumber int, rowsPerPage int) ([]Summary, error)
 	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryEach(ctx context.Context, filter QueryFilter, orderBy order.By, fn func(Summary) error) error
 }
 
 // =============================================================================
@@ -38,3 +39,13 @@ func (c *Core) Query(ctx context.Context, filter QueryFilter, orderBy order.By,
 func (c *Core) Count(ctx context.Context, filter QueryFilter) (int, error) {
 	return c.storer.Count(ctx, filter)
 }
+
+// QueryEach chama fn para cada resumo que atende ao filtro, na ordem
+// informada, sem carregar o relatório inteiro em memória
+func (c *Core) QueryEach(ctx context.Context, filter QueryFilter, orderBy order.By, fn func(Summary) error) error {
+	if err := c.storer.QueryEach(ctx, filter, orderBy, fn); err != nil {
+		return fmt.Errorf("queryeach: %w", err)
+	}
+
+	return nil
+}
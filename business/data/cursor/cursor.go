@@ -0,0 +1,257 @@
+// Package cursor provê suporte para paginação por cursor (keyset pagination).
+// Ao invés de pular linhas com OFFSET, que fica mais lento quanto mais
+// profunda a página, o cursor guarda a chave de ordenação da última linha vista
+// e a próxima consulta começa a partir dela
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/data/order"
+	"github.com/vitoraalmeida/service/business/sys/database/pgx/dbarray"
+	"github.com/vitoraalmeida/service/business/sys/validate"
+)
+
+// Define as direções de navegação a partir de um cursor
+const (
+	Next = "next"
+	Prev = "prev"
+)
+
+// Cursor representa a posição de uma linha dentro de uma ordenação. Para o
+// cliente ele é opaco, sendo trafegado apenas na forma codificada
+type Cursor struct {
+	Order     string   `json:"o"` // ordenação que gerou o cursor (order.By.String)
+	Values    []string `json:"v"` // valores dos campos de ordenação na linha, terminando na chave primária
+	Direction string   `json:"d"`
+}
+
+// IsZero informa se o cursor aponta para o início dos resultados
+func (c Cursor) IsZero() bool {
+	return len(c.Values) == 0
+}
+
+// Encode converte o cursor para a forma opaca que é entregue ao cliente
+func Encode(c Cursor) string {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode faz o caminho inverso de Encode. Uma string vazia representa o
+// início dos resultados
+func Decode(s string) (Cursor, error) {
+	if s == "" {
+		return Cursor{Direction: Next}, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, errors.New("malformed cursor")
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Cursor{}, errors.New("malformed cursor")
+	}
+
+	switch c.Direction {
+	case Next, Prev:
+	default:
+		return Cursor{}, fmt.Errorf("unknown cursor direction: %s", c.Direction)
+	}
+
+	if len(c.Values) == 0 {
+		return Cursor{}, errors.New("malformed cursor")
+	}
+
+	return c, nil
+}
+
+// =============================================================================
+
+// Page contém os cursores codificados para navegar para as páginas vizinhas.
+// Um cursor vazio indica que não há página naquela direção
+type Page struct {
+	Next string
+	Prev string
+}
+
+// Paginate recebe as linhas buscadas pelo store, que deve pedir ao banco uma
+// linha a mais do que rowsPerPage para sabermos se existe mais uma página
+// naquela direção. As linhas devem estar na ordem de apresentação, mesmo
+// quando a busca foi feita para trás. keyOf gera o cursor (sem direção) de uma
+// linha. Um rowsPerPage negativo é tratado como zero, para que um valor vindo
+// da requisição sem validação não derrube o handler
+func Paginate[T any](items []T, cur Cursor, rowsPerPage int, keyOf func(T) Cursor) ([]T, Page) {
+	rowsPerPage = max(rowsPerPage, 0)
+
+	hasMore := len(items) > rowsPerPage
+	if hasMore {
+		// quando navegamos para trás a linha extra fica no início
+		switch cur.Direction {
+		case Prev:
+			items = items[len(items)-rowsPerPage:]
+		default:
+			items = items[:rowsPerPage]
+		}
+	}
+
+	var page Page
+	if len(items) == 0 {
+		return items, page
+	}
+
+	hasNext := hasMore
+	hasPrev := !cur.IsZero()
+	if cur.Direction == Prev {
+		hasNext, hasPrev = !cur.IsZero(), hasMore
+	}
+
+	if hasNext {
+		c := keyOf(items[len(items)-1])
+		c.Direction = Next
+		page.Next = Encode(c)
+	}
+
+	if hasPrev {
+		c := keyOf(items[0])
+		c.Direction = Prev
+		page.Prev = Encode(c)
+	}
+
+	return items, page
+}
+
+// =============================================================================
+
+// Column descreve uma coluna da ordenação usada na consulta por cursor
+type Column struct {
+	Name      string // nome da coluna no banco
+	Type      string // tipo usado para converter o valor guardado no cursor
+	Direction string
+}
+
+// OrderByClause gera a ordenação da consulta por cursor. Ao navegar para trás
+// todas as direções são invertidas
+func OrderByClause(columns []Column, cur Cursor) string {
+	parts := make([]string, len(columns))
+	for i, col := range columns {
+		parts[i] = col.Name + " " + direction(col, cur)
+	}
+
+	return " ORDER BY " + strings.Join(parts, ", ")
+}
+
+// Clause gera a condição que posiciona a consulta logo depois (ou antes) da
+// linha apontada pelo cursor. Como cada coluna pode ter uma direção
+// diferente, a comparação é expandida:
+//
+//	(c1 > v1) OR (c1 = v1 AND c2 < v2) OR (c1 = v1 AND c2 = v2 AND id > v3)
+//
+// Os valores são passados como parâmetros nomeados em data. Como o cursor vem
+// do cliente sem assinatura, cada valor é validado contra o tipo da coluna
+// antes da consulta, e um cursor adulterado resulta em um FieldError
+func Clause(columns []Column, cur Cursor, data map[string]interface{}) (string, error) {
+	if len(cur.Values) != len(columns) {
+		return "", validate.NewFieldsError("cursor", errors.New("cursor does not match the order columns"))
+	}
+
+	params := make([]string, len(columns))
+	for i, col := range columns {
+		if err := checkValue(col.Type, cur.Values[i]); err != nil {
+			return "", validate.NewFieldsError("cursor", fmt.Errorf("value[%d]: %w", i, err))
+		}
+
+		name := fmt.Sprintf("cursor_%d", i)
+		data[name] = cur.Values[i]
+		params[i] = fmt.Sprintf("CAST(:%s AS %s)", name, col.Type)
+	}
+
+	ors := make([]string, len(columns))
+	for i, col := range columns {
+		ands := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, columns[j].Name+" = "+params[j])
+		}
+
+		op := ">"
+		if direction(col, cur) == order.DESC {
+			op = "<"
+		}
+		ands = append(ands, col.Name+" "+op+" "+params[i])
+
+		ors[i] = "(" + strings.Join(ands, " AND ") + ")"
+	}
+
+	return "(" + strings.Join(ors, " OR ") + ")", nil
+}
+
+// direction retorna a direção efetiva da coluna de acordo com a direção de
+// navegação do cursor
+func direction(col Column, cur Cursor) string {
+	if cur.Direction != Prev {
+		return col.Direction
+	}
+
+	if col.Direction == order.DESC {
+		return order.ASC
+	}
+	return order.DESC
+}
+
+// numericValue aceita a forma decimal com que os valores NUMERIC são gravados
+// no cursor
+var numericValue = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)$`)
+
+// checkValue verifica se o valor pode ser convertido para o tipo da coluna
+// pelo banco. Tipos desconhecidos são recusados, para que uma nova coluna de
+// ordenação precise ser tratada aqui
+func checkValue(typ string, v string) error {
+	// o banco não aceita NUL nem UTF-8 inválido em nenhum tipo textual
+	if !utf8.ValidString(v) || strings.ContainsRune(v, 0) {
+		return errors.New("invalid text")
+	}
+
+	var err error
+	switch strings.ToUpper(typ) {
+	case "TEXT":
+	case "UUID":
+		_, err = uuid.Parse(v)
+	case "INT", "INTEGER":
+		_, err = strconv.ParseInt(v, 10, 32)
+	case "BIGINT":
+		_, err = strconv.ParseInt(v, 10, 64)
+	case "NUMERIC":
+		if !numericValue.MatchString(v) {
+			err = errors.New("invalid number")
+		}
+	case "BOOLEAN":
+		_, err = strconv.ParseBool(v)
+	case "TIMESTAMP", "TIMESTAMPTZ":
+		_, err = time.Parse(time.RFC3339Nano, v)
+	case "TEXT[]":
+		var a dbarray.String
+		err = a.Scan(v)
+	default:
+		return fmt.Errorf("unsupported type %s", typ)
+	}
+
+	if err != nil {
+		return fmt.Errorf("invalid %s", strings.ToLower(typ))
+	}
+
+	return nil
+}
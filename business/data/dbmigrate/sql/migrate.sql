@@ -43,3 +43,162 @@ JOIN
     products AS p ON p.user_id = u.user_id
 GROUP BY
     u.user_id
+
+-- Version: 1.04
+-- Description: Add sold and revenue to products
+ALTER TABLE products
+	ADD COLUMN sold    INT            NOT NULL DEFAULT 0,
+	ADD COLUMN revenue NUMERIC(12, 2) NOT NULL DEFAULT 0;
+
+-- Version: 1.05
+-- Description: Create table sales
+CREATE TABLE sales (
+	sale_id      UUID           NOT NULL,
+	user_id      UUID           NOT NULL,
+	total        NUMERIC(12, 2) NOT NULL,
+	date_created TIMESTAMP      NOT NULL,
+
+	PRIMARY KEY (sale_id),
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+-- Version: 1.06
+-- Description: Create table sale_items
+CREATE TABLE sale_items (
+	sale_id    UUID           NOT NULL,
+	product_id UUID           NOT NULL,
+	quantity   INT            NOT NULL,
+	price      NUMERIC(10, 2) NOT NULL, -- custo unitário do produto no momento da venda
+
+	PRIMARY KEY (sale_id, product_id),
+	FOREIGN KEY (sale_id) REFERENCES sales(sale_id) ON DELETE CASCADE,
+	FOREIGN KEY (product_id) REFERENCES products(product_id) ON DELETE CASCADE
+);
+
+-- Version: 1.07
+-- Description: Create table inventory_movements
+CREATE TABLE inventory_movements (
+	movement_id  UUID      NOT NULL,
+	product_id   UUID      NOT NULL,
+	user_id      UUID      NOT NULL, -- quem realizou a movimentação
+	kind         TEXT      NOT NULL,
+	quantity     INT       NOT NULL, -- variação no estoque, negativa para saídas
+	reason       TEXT      NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (movement_id),
+	FOREIGN KEY (product_id) REFERENCES products(product_id) ON DELETE CASCADE,
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+CREATE INDEX inventory_movements_product_idx ON inventory_movements (product_id, date_created);
+
+-- Version: 1.08
+-- Description: Record opening balance of existing products in the ledger
+INSERT INTO inventory_movements
+	(movement_id, product_id, user_id, kind, quantity, reason, date_created)
+SELECT
+	gen_random_uuid(), product_id, user_id, 'ADJUSTMENT', quantity, 'opening balance', now() AT TIME ZONE 'utc'
+FROM
+	products
+WHERE
+	quantity <> 0;
+
+-- Version: 1.09
+-- Description: Create table exchange_rates
+CREATE TABLE exchange_rates (
+	currency     TEXT           NOT NULL,
+	rate         NUMERIC(18, 8) NOT NULL, -- unidades da moeda para uma unidade da moeda padrão
+	date_updated TIMESTAMP      NOT NULL,
+
+	PRIMARY KEY (currency),
+	CHECK (rate > 0)
+);
+INSERT INTO exchange_rates (currency, rate, date_updated) VALUES ('USD', 1, now() AT TIME ZONE 'utc');
+
+-- Version: 1.10
+-- Description: Add currency to products and sale_items
+ALTER TABLE products
+	ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD' REFERENCES exchange_rates(currency);
+ALTER TABLE sale_items
+	ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD';
+
+-- Version: 1.11
+-- Description: Aggregate user_summary costs in the default currency
+CREATE OR REPLACE VIEW user_summary AS
+SELECT
+    u.user_id             AS user_id,
+	u.name                AS user_name,
+    COUNT(p.*)            AS total_count,
+    SUM(p.cost / r.rate)  AS total_cost
+FROM
+    users AS u
+JOIN
+    products AS p ON p.user_id = u.user_id
+JOIN
+    exchange_rates AS r ON r.currency = p.currency
+GROUP BY
+    u.user_id;
+
+-- Version: 1.12
+-- Description: Create table categories
+CREATE TABLE categories (
+	category_id  UUID      NOT NULL,
+	parent_id    UUID      NULL, -- categorias sem pai ficam na raiz da árvore
+	name         TEXT      NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+	date_updated TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (category_id),
+	FOREIGN KEY (parent_id) REFERENCES categories(category_id) ON DELETE RESTRICT,
+	UNIQUE NULLS NOT DISTINCT (parent_id, name)
+);
+
+-- Version: 1.13
+-- Description: Add category and tags to products
+ALTER TABLE products
+	ADD COLUMN category_id UUID   NULL REFERENCES categories(category_id) ON DELETE SET NULL,
+	ADD COLUMN tags        TEXT[] NOT NULL DEFAULT '{}';
+CREATE INDEX products_category_idx ON products (category_id);
+CREATE INDEX products_tags_idx ON products USING GIN (tags);
+
+-- Version: 1.14
+-- Description: Add full-text search columns to users and products
+ALTER TABLE users
+	ADD COLUMN search TSVECTOR GENERATED ALWAYS AS (
+		setweight(to_tsvector('simple', name), 'A') ||
+		setweight(to_tsvector('simple', email), 'B') ||
+		setweight(to_tsvector('simple', coalesce(department, '')), 'C')
+	) STORED;
+CREATE INDEX users_search_idx ON users USING GIN (search);
+ALTER TABLE products
+	ADD COLUMN search TSVECTOR GENERATED ALWAYS AS (to_tsvector('simple', name)) STORED;
+CREATE INDEX products_search_idx ON products USING GIN (search);
+
+-- Version: 1.15
+-- Description: Create materialized copy of user_summary
+CREATE MATERIALIZED VIEW user_summary_mat AS
+SELECT
+	user_id, user_name, total_count, total_cost
+FROM
+	user_summary;
+-- o índice único é exigido pelo REFRESH MATERIALIZED VIEW CONCURRENTLY
+CREATE UNIQUE INDEX user_summary_mat_user_id_idx ON user_summary_mat (user_id);
+
+-- Version: 1.16
+-- Description: Keep sales history when users or products are deleted
+ALTER TABLE sales
+	DROP CONSTRAINT sales_user_id_fkey,
+	ADD CONSTRAINT sales_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE RESTRICT;
+ALTER TABLE sale_items
+	DROP CONSTRAINT sale_items_sale_id_fkey,
+	DROP CONSTRAINT sale_items_product_id_fkey,
+	ADD CONSTRAINT sale_items_sale_id_fkey FOREIGN KEY (sale_id) REFERENCES sales(sale_id) ON DELETE RESTRICT,
+	ADD CONSTRAINT sale_items_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(product_id) ON DELETE RESTRICT;
+
+-- Version: 1.17
+-- Description: Keep the inventory ledger when users or products are deleted
+ALTER TABLE inventory_movements
+	DROP CONSTRAINT inventory_movements_product_id_fkey,
+	DROP CONSTRAINT inventory_movements_user_id_fkey,
+	ADD CONSTRAINT inventory_movements_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(product_id) ON DELETE RESTRICT,
+	ADD CONSTRAINT inventory_movements_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE RESTRICT;
@@ -0,0 +1,274 @@
+// Package expr provê uma linguagem simples de expressões de filtro para os
+// endpoints de consulta. Um filtro é uma lista de condições separadas por
+// vírgula, combinadas com AND:
+//
+//	filter=cost>10,name~shirt,date_created>=2024-01-01
+//
+// Valores com vírgula devem estar entre aspas duplas ou ter a vírgula escapada
+// com barra invertida. \" e \\ representam uma aspa e uma barra no valor.
+// O parâmetro também pode ser repetido, uma condição por ocorrência:
+//
+//	filter=name~"shirt, blue"&filter=cost>10
+//	filter=name~shirt\, blue
+//
+// Cada domínio define quais campos aceita e o tipo de cada um, e cada store
+// define a coluna correspondente. As condições viram fragmentos SQL com
+// parâmetros nomeados, então o valor informado pelo cliente nunca é
+// concatenado na query
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitoraalmeida/service/business/sys/validate"
+)
+
+// Type representa o tipo de um campo que pode ser filtrado
+type Type int
+
+// Conjunto de tipos suportados
+const (
+	String Type = iota
+	Int
+	Float
+	Bool
+	Time
+	UUID
+)
+
+// Conjunto de operadores suportados
+const (
+	OpEQ   = "="
+	OpNE   = "!="
+	OpGT   = ">"
+	OpGTE  = ">="
+	OpLT   = "<"
+	OpLTE  = "<="
+	OpLike = "~" // contém o texto, sem diferenciar maiúsculas e minúsculas
+)
+
+// operadores em ordem de tentativa, os de dois caracteres primeiro
+var operators = []string{OpGTE, OpLTE, OpNE, OpEQ, OpGT, OpLT, OpLike}
+
+// operadores que cada tipo aceita
+var typeOperators = map[Type]map[string]struct{}{
+	String: {OpEQ: {}, OpNE: {}, OpGT: {}, OpGTE: {}, OpLT: {}, OpLTE: {}, OpLike: {}},
+	Int:    {OpEQ: {}, OpNE: {}, OpGT: {}, OpGTE: {}, OpLT: {}, OpLTE: {}},
+	Float:  {OpEQ: {}, OpNE: {}, OpGT: {}, OpGTE: {}, OpLT: {}, OpLTE: {}},
+	Time:   {OpEQ: {}, OpNE: {}, OpGT: {}, OpGTE: {}, OpLT: {}, OpLTE: {}},
+	Bool:   {OpEQ: {}, OpNE: {}},
+	UUID:   {OpEQ: {}, OpNE: {}},
+}
+
+// Fields é a lista de campos que um domínio permite filtrar e seus tipos
+type Fields map[string]Type
+
+// =============================================================================
+
+// Condition representa a comparação de um campo com um valor já convertido
+// para o tipo do campo
+type Condition struct {
+	Field string
+	Op    string
+	Value any
+}
+
+// Expression é o conjunto de condições de um filtro, combinadas com AND
+type Expression []Condition
+
+// Parse faz o parse do parâmetro "filter" da query string, aceitando apenas
+// os campos presentes em fields. Quando o parâmetro é repetido, as condições
+// de todas as ocorrências são combinadas
+func Parse(r *http.Request, fields Fields) (Expression, error) {
+	var exp Expression
+	for _, v := range r.URL.Query()["filter"] {
+		e, err := ParseString(v, fields)
+		if err != nil {
+			return nil, validate.NewFieldsError("filter", err)
+		}
+		exp = append(exp, e...)
+	}
+
+	return exp, nil
+}
+
+// ParseString faz o parse de uma expressão de filtro
+func ParseString(s string, fields Fields) (Expression, error) {
+	terms, err := splitTerms(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var exp Expression
+	for _, term := range terms {
+		cond, err := parseCondition(term, fields)
+		if err != nil {
+			return nil, err
+		}
+
+		exp = append(exp, cond)
+	}
+
+	return exp, nil
+}
+
+// splitTerms separa a expressão nas vírgulas que estão fora de aspas,
+// removendo as aspas e resolvendo os escapes. Espaços nas pontas de cada
+// termo e do valor são descartados, mesmo entre aspas
+func splitTerms(s string) ([]string, error) {
+	var terms []string
+	var b strings.Builder
+	var quoted bool
+
+	flush := func() {
+		if t := strings.TrimSpace(b.String()); t != "" {
+			terms = append(terms, t)
+		}
+		b.Reset()
+	}
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\':
+			if i+1 == len(s) {
+				return nil, errors.New("unterminated escape at end of filter")
+			}
+			i++
+			b.WriteByte(s[i])
+		case c == '"':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			flush()
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	if quoted {
+		return nil, errors.New("unterminated quote in filter")
+	}
+	flush()
+
+	return terms, nil
+}
+
+// parseCondition separa um termo em campo, operador e valor e converte o
+// valor para o tipo do campo
+func parseCondition(term string, fields Fields) (Condition, error) {
+	// o nome do campo é formado apenas por letras, números e _
+	i := strings.IndexFunc(term, func(r rune) bool {
+		return !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+	if i <= 0 {
+		return Condition{}, fmt.Errorf("malformed condition %q", term)
+	}
+
+	field, rest := term[:i], term[i:]
+
+	typ, exists := fields[field]
+	if !exists {
+		return Condition{}, fmt.Errorf("unknown field %q", field)
+	}
+
+	var op string
+	for _, o := range operators {
+		if strings.HasPrefix(rest, o) {
+			op = o
+			break
+		}
+	}
+	if op == "" {
+		return Condition{}, fmt.Errorf("unknown operator in %q", term)
+	}
+
+	if _, ok := typeOperators[typ][op]; !ok {
+		return Condition{}, fmt.Errorf("operator %q not supported for field %q", op, field)
+	}
+
+	value, err := parseValue(typ, strings.TrimSpace(rest[len(op):]))
+	if err != nil {
+		return Condition{}, fmt.Errorf("field %q: %w", field, err)
+	}
+
+	cond := Condition{
+		Field: field,
+		Op:    op,
+		Value: value,
+	}
+
+	return cond, nil
+}
+
+// parseValue converte o valor textual para o tipo do campo
+func parseValue(typ Type, value string) (any, error) {
+	if value == "" {
+		return nil, errors.New("missing value")
+	}
+
+	switch typ {
+	case Int:
+		return strconv.Atoi(value)
+	case Float:
+		return strconv.ParseFloat(value, 64)
+	case Bool:
+		return strconv.ParseBool(value)
+	case UUID:
+		return uuid.Parse(value)
+	case Time:
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t.UTC(), nil
+		}
+		t, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return nil, errors.New("expected RFC3339 or YYYY-MM-DD date")
+		}
+		return t, nil
+	}
+
+	return value, nil
+}
+
+// =============================================================================
+
+// Clauses converte a expressão em cláusulas para o WHERE de uma query.
+// columns mapeia cada campo para a coluna no banco. Os valores são adicionados
+// em data para serem substituídos pelo sqlx como parâmetros nomeados
+func (exp Expression) Clauses(columns map[string]string, data map[string]interface{}) ([]string, error) {
+	wc := make([]string, 0, len(exp))
+
+	for i, cond := range exp {
+		column, exists := columns[cond.Field]
+		if !exists {
+			return nil, fmt.Errorf("field %q has no column", cond.Field)
+		}
+
+		// prefixo evita colisão com os parâmetros usados pelo restante da query
+		param := fmt.Sprintf("expr_%d", i)
+
+		switch cond.Op {
+		case OpLike:
+			data[param] = "%" + escapeLike(fmt.Sprint(cond.Value)) + "%"
+			wc = append(wc, column+" ILIKE :"+param)
+		case OpNE:
+			data[param] = cond.Value
+			wc = append(wc, column+" <> :"+param)
+		default:
+			data[param] = cond.Value
+			wc = append(wc, column+" "+cond.Op+" :"+param)
+		}
+	}
+
+	return wc, nil
+}
+
+// escapeLike faz com que os caracteres especiais do LIKE sejam tratados como
+// texto comum
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
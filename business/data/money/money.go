@@ -0,0 +1,335 @@
+// Package money fornece um tipo para valores monetários com aritmética em
+// ponto fixo. O valor é guardado em centavos (unidades menores da moeda), o
+// que evita os erros de arredondamento de float64 ao somar custos e receitas.
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/vitoraalmeida/service/business/sys/errs"
+)
+
+// DefaultCurrency é a moeda em que os valores são armazenados no banco de dados
+const DefaultCurrency = "USD"
+
+// número de casas decimais suportadas, igual à escala das colunas NUMERIC
+const scale = 2
+
+// fator entre a unidade da moeda e a unidade menor (centavos)
+const factor = 100
+
+// ErrCurrencyMismatch é retornado ao operar valores de moedas diferentes
+var ErrCurrencyMismatch = errs.New(errs.CurrencyMismatch, "currency mismatch")
+
+// Money representa um valor monetário numa moeda. O valor zero é zero na
+// moeda padrão
+type Money struct {
+	amount   int64  // valor em unidades menores da moeda
+	currency string // código ISO 4217
+}
+
+// New constrói um Money a partir do valor em unidades menores (centavos)
+func New(minor int64, currency string) Money {
+	return Money{
+		amount:   minor,
+		currency: currency,
+	}
+}
+
+// Parse converte um texto decimal como "12.34" num Money. Mais de duas casas
+// decimais são rejeitadas para não perder precisão silenciosamente
+func Parse(value string, currency string) (Money, error) {
+	if err := ValidateCurrency(currency); err != nil {
+		return Money{}, err
+	}
+
+	minor, err := parse(value, scale, true)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return New(minor, currency), nil
+}
+
+// MustParse chama panic() caso Parse retorne erro
+func MustParse(value string, currency string) Money {
+	m, err := Parse(value, currency)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+// FromFloat converte um float64 num Money arredondando para o centavo mais
+// próximo. Deve ser usado apenas na fronteira com dados que já chegam como
+// float, como números em JSON
+func FromFloat(value float64, currency string) Money {
+	return New(int64(math.Round(value*factor)), currency)
+}
+
+// =============================================================================
+
+// Minor retorna o valor em unidades menores da moeda (centavos)
+func (m Money) Minor() int64 {
+	return m.amount
+}
+
+// Currency retorna o código da moeda
+func (m Money) Currency() string {
+	if m.currency == "" {
+		return DefaultCurrency
+	}
+	return m.currency
+}
+
+// IsZero indica se o valor é zero
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+// IsNegative indica se o valor é menor que zero
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+// Add soma dois valores da mesma moeda
+func (m Money) Add(m2 Money) (Money, error) {
+	if m.Currency() != m2.Currency() {
+		return Money{}, fmt.Errorf("add %s to %s: %w", m2.Currency(), m.Currency(), ErrCurrencyMismatch)
+	}
+
+	return New(m.amount+m2.amount, m.Currency()), nil
+}
+
+// Sub subtrai dois valores da mesma moeda
+func (m Money) Sub(m2 Money) (Money, error) {
+	if m.Currency() != m2.Currency() {
+		return Money{}, fmt.Errorf("sub %s from %s: %w", m2.Currency(), m.Currency(), ErrCurrencyMismatch)
+	}
+
+	return New(m.amount-m2.amount, m.Currency()), nil
+}
+
+// Mul multiplica o valor por uma quantidade inteira
+func (m Money) Mul(quantity int64) Money {
+	return New(m.amount*quantity, m.Currency())
+}
+
+// Cmp compara dois valores da mesma moeda, retornando -1, 0 ou 1
+func (m Money) Cmp(m2 Money) (int, error) {
+	if m.Currency() != m2.Currency() {
+		return 0, fmt.Errorf("compare %s to %s: %w", m2.Currency(), m.Currency(), ErrCurrencyMismatch)
+	}
+
+	switch {
+	case m.amount < m2.amount:
+		return -1, nil
+	case m.amount > m2.amount:
+		return 1, nil
+	}
+
+	return 0, nil
+}
+
+// Equal provê suporte para o pacote go-cmp e testing
+func (m Money) Equal(m2 Money) bool {
+	return m.amount == m2.amount && m.Currency() == m2.Currency()
+}
+
+// String retorna o valor decimal sem a moeda, como "12.34"
+func (m Money) String() string {
+	sign := ""
+	amount := m.amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/factor, scale, amount%factor)
+}
+
+// =============================================================================
+
+// representação em JSON. O valor vai como texto para que clientes não
+// percam precisão ao converter para ponto flutuante
+type jsonMoney struct {
+	Amount   json.Number `json:"amount"`
+	Currency string      `json:"currency"`
+}
+
+// MarshalJSON converte Money para {"amount":"12.34","currency":"USD"}
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{
+		Amount:   m.String(),
+		Currency: m.Currency(),
+	})
+}
+
+// UnmarshalJSON aceita o objeto {"amount","currency"} ou apenas o valor, como
+// texto ou número. Sem moeda, assume a moeda padrão
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var jm jsonMoney
+
+	switch trimmed := strings.TrimSpace(string(data)); {
+	case strings.HasPrefix(trimmed, "{"):
+		if err := json.Unmarshal(data, &jm); err != nil {
+			return fmt.Errorf("money: %w", err)
+		}
+	default:
+		if err := json.Unmarshal(data, &jm.Amount); err != nil {
+			return fmt.Errorf("money: %w", err)
+		}
+	}
+
+	currency := jm.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+
+	v, err := Parse(jm.Amount.String(), currency)
+	if err != nil {
+		return fmt.Errorf("money: %w", err)
+	}
+
+	*m = v
+	return nil
+}
+
+// Value implementa driver.Valuer. O valor é enviado como texto, que o banco
+// converte para NUMERIC sem perda
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan implementa sql.Scanner para colunas NUMERIC. Como o banco não guarda a
+// moeda, o valor lido está sempre na moeda padrão. Casas decimais além da
+// escala, como em resultados de divisões, são arredondadas
+func (m *Money) Scan(src any) error {
+	var minor int64
+	var err error
+
+	switch v := src.(type) {
+	case nil:
+		minor = 0
+	case string:
+		minor, err = parse(v, scale, false)
+	case []byte:
+		minor, err = parse(string(v), scale, false)
+	case int64:
+		minor = v * factor
+	case float64:
+		minor = int64(math.Round(v * factor))
+	default:
+		return fmt.Errorf("money: unsupported scan type %T", src)
+	}
+
+	if err != nil {
+		return fmt.Errorf("money: %w", err)
+	}
+
+	*m = New(minor, DefaultCurrency)
+	return nil
+}
+
+// =============================================================================
+
+// parse converte um texto decimal num inteiro com a quantidade de casas
+// decimais definida por scale. Se strict for falso, casas decimais além da
+// escala são arredondadas ao invés de rejeitadas
+func parse(value string, scale int, strict bool) (int64, error) {
+	s := strings.TrimSpace(value)
+
+	neg := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg = true
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+
+	// dígitos além da escala decidem o arredondamento
+	roundUp := false
+	if len(fracPart) > scale {
+		if strict {
+			return 0, fmt.Errorf("invalid amount %q: more than %d decimal places", value, scale)
+		}
+		if !isDigits(fracPart[scale:]) {
+			return 0, fmt.Errorf("invalid amount %q", value)
+		}
+		roundUp = fracPart[scale] >= '5'
+		fracPart = fracPart[:scale]
+	}
+	fracPart += strings.Repeat("0", scale-len(fracPart))
+
+	if !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+
+	units, err := strconv.ParseInt(intPart, 10, 64)
+	factor := pow10(scale)
+	if err != nil || units > math.MaxInt64/factor-1 {
+		return 0, fmt.Errorf("invalid amount %q: out of range", value)
+	}
+
+	cents, _ := strconv.ParseInt(fracPart, 10, 64)
+
+	minor := units*factor + cents
+	if roundUp {
+		minor++
+	}
+	if neg {
+		minor = -minor
+	}
+
+	return minor, nil
+}
+
+// pow10 retorna 10 elevado a n
+func pow10(n int) int64 {
+	p := int64(1)
+	for i := 0; i < n; i++ {
+		p *= 10
+	}
+	return p
+}
+
+// isDigits indica se o texto contém apenas dígitos decimais
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateCurrency checa se o código da moeda tem o formato ISO 4217, como "EUR"
+func ValidateCurrency(currency string) error {
+	if len(currency) != 3 {
+		return fmt.Errorf("invalid currency %q", currency)
+	}
+	for _, r := range currency {
+		if r < 'A' || r > 'Z' {
+			return fmt.Errorf("invalid currency %q", currency)
+		}
+	}
+	return nil
+}
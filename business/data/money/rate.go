@@ -0,0 +1,146 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/vitoraalmeida/service/business/sys/errs"
+)
+
+// número de casas decimais de uma taxa de câmbio, igual à escala da coluna
+// exchange_rates.rate
+const rateScale = 8
+
+// ErrInvalidRate é retornado para taxas de câmbio menores ou iguais a zero
+var ErrInvalidRate = errs.New(errs.InvalidRate, "rate must be greater than zero")
+
+// Rate representa uma taxa de câmbio em ponto fixo: quantas unidades de uma
+// moeda valem uma unidade da moeda padrão
+type Rate struct {
+	value int64 // taxa multiplicada por 10^rateScale
+}
+
+// ParseRate converte um texto decimal como "1.0823" numa taxa de câmbio
+func ParseRate(value string) (Rate, error) {
+	v, err := parse(value, rateScale, true)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	if v <= 0 {
+		return Rate{}, ErrInvalidRate
+	}
+
+	return Rate{value: v}, nil
+}
+
+// MustParseRate chama panic() caso ParseRate retorne erro
+func MustParseRate(value string) Rate {
+	r, err := ParseRate(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return r
+}
+
+// IsZero indica se a taxa não foi definida
+func (r Rate) IsZero() bool {
+	return r.value == 0
+}
+
+// String retorna a taxa em texto decimal, sem zeros à direita
+func (r Rate) String() string {
+	factor := pow10(rateScale)
+	s := fmt.Sprintf("%d.%0*d", r.value/factor, rateScale, r.value%factor)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// MarshalJSON converte a taxa para texto, preservando todas as casas decimais
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON aceita a taxa como texto ou número
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("rate: %w", err)
+	}
+
+	v, err := ParseRate(n.String())
+	if err != nil {
+		return fmt.Errorf("rate: %w", err)
+	}
+
+	*r = v
+	return nil
+}
+
+// Value implementa driver.Valuer
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// Scan implementa sql.Scanner para colunas NUMERIC
+func (r *Rate) Scan(src any) error {
+	var s string
+
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("rate: unsupported scan type %T", src)
+	}
+
+	v, err := parse(s, rateScale, false)
+	if err != nil {
+		return fmt.Errorf("rate: %w", err)
+	}
+
+	r.value = v
+	return nil
+}
+
+// =============================================================================
+
+// Convert converte um valor de uma moeda para outra. from é a taxa da moeda
+// do valor e to a taxa da moeda de destino, ambas em relação à moeda padrão.
+// O cálculo é feito com números racionais e arredondado uma única vez
+func Convert(m Money, from Rate, to Rate, currency string) (Money, error) {
+	if from.IsZero() || to.IsZero() {
+		return Money{}, ErrInvalidRate
+	}
+
+	if err := ValidateCurrency(currency); err != nil {
+		return Money{}, err
+	}
+
+	// minor * to / from
+	num := new(big.Int).Mul(big.NewInt(m.amount), big.NewInt(to.value))
+	den := big.NewInt(from.value)
+
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+
+	// arredonda para longe do zero quando o resto for pelo menos metade
+	twice := new(big.Int).Lsh(new(big.Int).Abs(rem), 1)
+	if rem.Sign() != 0 && twice.Cmp(den) >= 0 {
+		if num.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+
+	if !q.IsInt64() {
+		return Money{}, fmt.Errorf("convert %s to %s: out of range", m.Currency(), currency)
+	}
+
+	return New(q.Int64(), currency), nil
+}
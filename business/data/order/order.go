@@ -23,24 +23,54 @@ var directions = map[string]string{
 
 // =============================================================================
 
-// By representa um campo para ser ordenado e uma direção
-type By struct {
-	Field     string
+// Field representa um campo para ser ordenado e uma direção
+type Field struct {
+	Name      string
 	Direction string
 }
 
-// NewBy contrói um By
+// By representa os campos usados na ordenação, em ordem de prioridade. O
+// segundo campo só é usado para desempatar linhas com o mesmo valor no
+// primeiro, e assim por diante
+type By struct {
+	Fields []Field
+}
+
+// NewBy contrói um By com um único campo
 func NewBy(field string, direction string) By {
 	return By{
-		Field:     field,
-		Direction: direction,
+		Fields: []Field{
+			{Name: field, Direction: direction},
+		},
 	}
 }
 
+// Then retorna uma cópia de By com mais um campo de desempate
+func (b By) Then(field string, direction string) By {
+	fields := make([]Field, len(b.Fields), len(b.Fields)+1)
+	copy(fields, b.Fields)
+
+	return By{
+		Fields: append(fields, Field{Name: field, Direction: direction}),
+	}
+}
+
+// String retorna a ordenação no mesmo formato aceito por Parse
+func (b By) String() string {
+	parts := make([]string, len(b.Fields))
+	for i, fld := range b.Fields {
+		parts[i] = fld.Name + "," + fld.Direction
+	}
+
+	return strings.Join(parts, ";")
+}
+
 // =============================================================================
 
-// Parse constrói um valor By fazendo um parsing de uma string na forma "field,direction"
-func Parse(r *http.Request, defaultOrder By) (By, error) {
+// Parse constrói um valor By fazendo um parsing de uma string na forma
+// "field,direction;field,direction". Os campos são aceitos apenas se
+// estiverem presentes em fields, o conjunto de campos ordenáveis do domínio
+func Parse(r *http.Request, defaultOrder By, fields map[string]struct{}) (By, error) {
 	// busca infos de como o usuário solicitou a filtragem/ordenação
 	v := r.URL.Query().Get("orderBy")
 
@@ -48,22 +78,48 @@ func Parse(r *http.Request, defaultOrder By) (By, error) {
 		return defaultOrder, nil
 	}
 
+	var by By
+	seen := make(map[string]struct{})
+
+	for _, part := range strings.Split(v, ";") {
+		fld, err := parseField(part)
+		if err != nil {
+			return By{}, validate.NewFieldsError(v, err)
+		}
+
+		if _, exists := fields[fld.Name]; !exists {
+			return By{}, validate.NewFieldsError(fld.Name, errors.New("order field does not exist"))
+		}
+
+		if _, exists := seen[fld.Name]; exists {
+			return By{}, validate.NewFieldsError(fld.Name, errors.New("order field repeated"))
+		}
+		seen[fld.Name] = struct{}{}
+
+		by.Fields = append(by.Fields, fld)
+	}
+
+	return by, nil
+}
+
+// parseField faz o parsing de um único par "field,direction"
+func parseField(v string) (Field, error) {
 	orderParts := strings.Split(v, ",")
 
-	var by By
+	var fld Field
 	switch len(orderParts) {
 	case 1:
 		// se não foi passado uma direção, usamos ASC por padrão
-		by = NewBy(strings.Trim(orderParts[0], " "), ASC)
+		fld = Field{Name: strings.Trim(orderParts[0], " "), Direction: ASC}
 	case 2:
-		by = NewBy(strings.Trim(orderParts[0], " "), strings.Trim(orderParts[1], " "))
+		fld = Field{Name: strings.Trim(orderParts[0], " "), Direction: strings.Trim(orderParts[1], " ")}
 	default:
-		return By{}, validate.NewFieldsError(v, errors.New("unknown order field"))
+		return Field{}, errors.New("unknown order field")
 	}
 
-	if _, exists := directions[by.Direction]; !exists {
-		return By{}, validate.NewFieldsError(v, fmt.Errorf("unknown direction: %s", by.Direction))
+	if _, exists := directions[fld.Direction]; !exists {
+		return Field{}, fmt.Errorf("unknown direction: %s", fld.Direction)
 	}
 
-	return by, nil
+	return fld, nil
 }
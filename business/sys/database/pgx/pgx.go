@@ -21,17 +21,46 @@ import (
 )
 
 const (
-	uniqueViolation = "23505"
-	undefinedTable  = "42P01"
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+	undefinedTable      = "42P01"
 )
 
 // Conjunto de erros para operações CRUD
 var (
 	ErrDBNotFound        = sql.ErrNoRows
 	ErrDBDuplicatedEntry = errors.New("duplicated entry")
+	ErrDBForeignKey      = errors.New("foreign key violation")
 	ErrUndefinedTable    = errors.New("undefined table")
 )
 
+// ForeignKeyError é retornado em violações de chave estrangeira, informando a
+// constraint violada para que o store saiba qual relação falhou. Pode ser
+// verificado com errors.Is(err, ErrDBForeignKey)
+type ForeignKeyError struct {
+	Constraint string
+}
+
+// Error implementa a interface error
+func (fke *ForeignKeyError) Error() string {
+	return fmt.Sprintf("foreign key violation: constraint[%s]", fke.Constraint)
+}
+
+// Is faz com que errors.Is(err, ErrDBForeignKey) continue valendo
+func (fke *ForeignKeyError) Is(target error) bool {
+	return target == ErrDBForeignKey
+}
+
+// ForeignKeyConstraint retorna o nome da constraint violada, ou vazio quando o
+// erro não é uma violação de chave estrangeira
+func ForeignKeyConstraint(err error) string {
+	var fke *ForeignKeyError
+	if !errors.As(err, &fke) {
+		return ""
+	}
+	return fke.Constraint
+}
+
 // Config representa propriedades necessáiras para usar o banco de dados
 type Config struct {
 	User         string
@@ -42,6 +71,14 @@ type Config struct {
 	MaxIdleConns int
 	MaxOpenConns int
 	DisableTLS   bool
+
+	// SlowQueryThreshold é a duração a partir da qual uma query é registrada
+	// como lenta, em nível Warn. Zero desativa o aviso
+	SlowQueryThreshold time.Duration
+
+	// LogQueryValues inclui os valores dos parâmetros nos logs das queries.
+	// Desativado por padrão para não registrar dados sensíveis
+	LogQueryValues bool
 }
 
 // Open abre uma conexão com base em Config
@@ -73,6 +110,8 @@ func Open(cfg Config) (*sqlx.DB, error) {
 	db.SetMaxIdleConns(cfg.MaxIdleConns)
 	db.SetMaxOpenConns(cfg.MaxOpenConns)
 
+	setQueryLogging(cfg.SlowQueryThreshold, cfg.LogQueryValues)
+
 	return db, nil
 }
 
@@ -152,15 +191,16 @@ func ExecContext(ctx context.Context, log *zap.SugaredLogger, db sqlx.ExtContext
 
 // ExecContext função helper para executar operações CUD com logging e tracing
 // que necessitam de substituição de campos
-func NamedExecContext(ctx context.Context, log *zap.SugaredLogger, db sqlx.ExtContext, query string, data any) error {
-	q := queryString(query, data)
-
+func NamedExecContext(ctx context.Context, log *zap.SugaredLogger, db sqlx.ExtContext, query string, data any) (err error) {
+	// quando chamada por ExecContext há um frame a mais até quem fez a query
+	skip := 2
 	if _, ok := data.(struct{}); ok {
-		log.WithOptions(zap.AddCallerSkip(3)).Infow("database.NamedExecContext", "trace_id", web.GetTraceID(ctx), "query", q)
-	} else {
-		log.WithOptions(zap.AddCallerSkip(2)).Infow("database.NamedExecContext", "trace_id", web.GetTraceID(ctx), "query", q)
+		skip = 3
 	}
 
+	ctx, obs := startQuery(ctx, log, opExec, query, data, skip)
+	defer func() { obs.end(err) }()
+
 	/*
 	   // Named queries, using `:name` as the bindvar.  Automatic bindvar support
 	   // which takes into account the dbtype based on the driverName on sqlx.Open/Connect
@@ -180,6 +220,8 @@ func NamedExecContext(ctx context.Context, log *zap.SugaredLogger, db sqlx.ExtCo
 				return ErrUndefinedTable
 			case uniqueViolation:
 				return ErrDBDuplicatedEntry
+			case foreignKeyViolation:
+				return &ForeignKeyError{Constraint: pqerr.ConstraintName}
 			}
 		}
 		return err
@@ -209,7 +251,10 @@ func NamedQuerySliceUsingIn[T any](ctx context.Context, log *zap.SugaredLogger,
 	return namedQuerySlice(ctx, log, db, query, data, dest, true)
 }
 
-func namedQuerySlice[T any](ctx context.Context, log *zap.SugaredLogger, db sqlx.ExtContext, query string, data any, dest *[]T, withIn bool) error {
+func namedQuerySlice[T any](ctx context.Context, log *zap.SugaredLogger, db sqlx.ExtContext, query string, data any, dest *[]T, withIn bool) (err error) {
+	ctx, obs := startQuery(ctx, log, opQuerySlice, query, data, 3)
+	defer func() { obs.end(err) }()
+
 	/*
 		data é o map usado para armazenar quais campos serão
 		passado na query para selecionar por valores
@@ -219,12 +264,7 @@ func namedQuerySlice[T any](ctx context.Context, log *zap.SugaredLogger, db sqlx
 		data[user_id] = *user.ID
 		data[name] = *user.Name
 	*/
-	q := queryString(query, data)
-
-	log.WithOptions(zap.AddCallerSkip(3)).Infow("database.NamedQuerySlice", "trace_id", web.GetTraceID(ctx), "query", q)
-
 	var rows *sqlx.Rows
-	var err error
 
 	switch withIn {
 	case true:
@@ -275,6 +315,37 @@ func namedQuerySlice[T any](ctx context.Context, log *zap.SugaredLogger, db sqlx
 	return nil
 }
 
+// NamedQueryEach executa a query e chama fn para cada linha retornada, sem
+// carregar o resultado inteiro em memória. Útil para exportações grandes. A
+// leitura é interrompida quando fn retorna erro ou quando o contexto é cancelado
+func NamedQueryEach[T any](ctx context.Context, log *zap.SugaredLogger, db sqlx.ExtContext, query string, data any, fn func(T) error) (err error) {
+	ctx, obs := startQuery(ctx, log, opQueryEach, query, data, 2)
+	defer func() { obs.end(err) }()
+
+	rows, err := sqlx.NamedQueryContext(ctx, db, query, data)
+	if err != nil {
+		if pqerr, ok := err.(*pgconn.PgError); ok && pqerr.Code == undefinedTable {
+			return ErrUndefinedTable
+		}
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v T
+		if err := rows.StructScan(&v); err != nil {
+			return err
+		}
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+
+	// rows.Next retorna falso tanto no fim das linhas quanto em caso de erro,
+	// como o cancelamento do contexto
+	return rows.Err()
+}
+
 // QueryStruct função para executar queries que retornam um único valor para
 // ser convertido num struct
 func QueryStruct(ctx context.Context, log *zap.SugaredLogger, db sqlx.ExtContext, query string, dest any) error {
@@ -294,13 +365,11 @@ func NamedQueryStructUsingIn(ctx context.Context, log *zap.SugaredLogger, db sql
 	return namedQueryStruct(ctx, log, db, query, data, dest, true)
 }
 
-func namedQueryStruct(ctx context.Context, log *zap.SugaredLogger, db sqlx.ExtContext, query string, data any, dest any, withIn bool) error {
-	q := queryString(query, data)
-
-	log.WithOptions(zap.AddCallerSkip(3)).Infow("database.NamedQueryStruct", "trace_id", web.GetTraceID(ctx), "query", q)
+func namedQueryStruct(ctx context.Context, log *zap.SugaredLogger, db sqlx.ExtContext, query string, data any, dest any, withIn bool) (err error) {
+	ctx, obs := startQuery(ctx, log, opQueryStruct, query, data, 3)
+	defer func() { obs.end(err) }()
 
 	var rows *sqlx.Rows
-	var err error
 
 	switch withIn {
 	case true:
@@ -350,7 +419,8 @@ func namedQueryStruct(ctx context.Context, log *zap.SugaredLogger, db sqlx.ExtCo
 	return nil
 }
 
-// queryString fornece uma versão legível das queries e parametros
+// queryString fornece uma versão legível das queries e parametros. Só é usada
+// nos logs quando LogQueryValues está ativo
 func queryString(query string, args any) string {
 	query, params, err := sqlx.Named(query, args)
 	if err != nil {
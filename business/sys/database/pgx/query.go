@@ -0,0 +1,176 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vitoraalmeida/service/foundation/web"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Tipos de operação usados nos logs e nas métricas das queries
+const (
+	opExec        = "exec"
+	opQuerySlice  = "query_slice"
+	opQueryStruct = "query_struct"
+	opQueryEach   = "query_each"
+)
+
+// queryLogging define como as queries são registradas nos logs. É configurado
+// por Open e vale para todos os helpers do pacote
+type queryLogging struct {
+	slowThreshold time.Duration
+	logValues     bool
+}
+
+var logging atomic.Pointer[queryLogging]
+
+func init() {
+	logging.Store(&queryLogging{})
+}
+
+// setQueryLogging altera a configuração de logging das queries. Um limite
+// zerado desativa o aviso de queries lentas
+func setQueryLogging(slowThreshold time.Duration, logValues bool) {
+	logging.Store(&queryLogging{
+		slowThreshold: slowThreshold,
+		logValues:     logValues,
+	})
+}
+
+var (
+	queriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "sales",
+		Name:      "db_queries_total",
+		Help:      "Total de queries executadas por tipo de operação.",
+	}, []string{"operation", "status"})
+
+	queryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "sales",
+		Name:      "db_query_duration_seconds",
+		Help:      "Duração das queries por tipo de operação.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation"})
+
+	slowQueriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "sales",
+		Name:      "db_slow_queries_total",
+		Help:      "Total de queries acima do limite de query lenta.",
+	}, []string{"operation"})
+)
+
+// Collectors retorna as métricas das queries para serem registradas por quem
+// expõe as métricas da aplicação
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{queriesTotal, queryDuration, slowQueriesTotal}
+}
+
+// queryObserver acompanha a execução de uma query: span, duração, métricas e
+// o log feito ao final
+type queryObserver struct {
+	log       *zap.SugaredLogger
+	span      trace.Span
+	traceID   string
+	operation string
+	query     string
+	data      any
+	caller    zapcore.EntryCaller
+	start     time.Time
+}
+
+// startQuery inicia a observação de uma query. skip é a quantidade de frames
+// entre startQuery e o código que chamou o pacote, usado para registrar no log
+// quem fez a query e não o helper
+func startQuery(ctx context.Context, log *zap.SugaredLogger, operation string, query string, data any, skip int) (context.Context, *queryObserver) {
+	ctx, span := startSpan(ctx, "database."+operation, query)
+
+	var caller zapcore.EntryCaller
+	if pc, file, line, ok := runtime.Caller(skip); ok {
+		caller = zapcore.EntryCaller{
+			Defined: true,
+			PC:      pc,
+			File:    file,
+			Line:    line,
+		}
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			caller.Function = fn.Name()
+		}
+	}
+
+	obs := queryObserver{
+		log:       log,
+		span:      span,
+		traceID:   web.GetTraceID(ctx),
+		operation: operation,
+		query:     query,
+		data:      data,
+		caller:    caller,
+		start:     time.Now(),
+	}
+
+	return ctx, &obs
+}
+
+// end finaliza a observação. A query é registrada depois de executada para
+// que a duração faça parte do log. Queries acima do limite configurado são
+// registradas em nível Warn
+func (o *queryObserver) end(err error) {
+	duration := time.Since(o.start)
+	endSpan(o.span, err)
+
+	status := "ok"
+	switch {
+	case errors.Is(err, ErrDBNotFound):
+		status = "not_found"
+	case err != nil:
+		status = "error"
+	}
+	queriesTotal.WithLabelValues(o.operation, status).Inc()
+	queryDuration.WithLabelValues(o.operation).Observe(duration.Seconds())
+
+	cfg := logging.Load()
+
+	q := compactQuery(o.query)
+	if cfg.logValues {
+		q = queryString(o.query, o.data)
+	}
+
+	lvl := zapcore.InfoLevel
+	msg := "database." + o.operation
+	fields := []zap.Field{
+		zap.String("trace_id", o.traceID),
+		zap.String("query", q),
+		zap.String("duration", duration.String()),
+	}
+
+	if cfg.slowThreshold > 0 && duration >= cfg.slowThreshold {
+		slowQueriesTotal.WithLabelValues(o.operation).Inc()
+
+		lvl = zapcore.WarnLevel
+		msg = "database slow query"
+		fields = append(fields, zap.String("threshold", cfg.slowThreshold.String()))
+	}
+
+	// o caller é informado manualmente, pois o frame de quem fez a query foi
+	// capturado no início da execução
+	ce := o.log.Desugar().WithOptions(zap.WithCaller(false)).Check(lvl, msg)
+	if ce == nil {
+		return
+	}
+	ce.Caller = o.caller
+	ce.Write(fields...)
+}
+
+// compactQuery coloca a query em uma única linha mantendo os nomes dos
+// parâmetros no lugar dos valores, para que dados sensíveis como hashes de
+// senha não cheguem aos logs
+func compactQuery(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
@@ -0,0 +1,31 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vitoraalmeida/service/foundation/web"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startSpan cria o span de uma operação no banco, filho do span da
+// requisição. A query registrada é a original, com os nomes dos parâmetros e
+// sem os valores, para que dados sensíveis não sejam exportados
+func startSpan(ctx context.Context, operation string, query string) (context.Context, trace.Span) {
+	return web.AddSpan(ctx, operation,
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.statement", query),
+	)
+}
+
+// endSpan finaliza o span registrando o erro da operação, se houver. Não
+// encontrar um registro não é considerado erro
+func endSpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, ErrDBNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
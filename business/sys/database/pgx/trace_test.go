@@ -0,0 +1,94 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	database "github.com/vitoraalmeida/service/business/sys/database/pgx"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+)
+
+func TestQuerySpan(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "request")
+
+	db := sqlx.NewDb(sql.OpenDB(failingConnector{}), "pgx")
+	defer db.Close()
+
+	const q = `UPDATE users SET password_hash = :password_hash WHERE user_id = :user_id`
+	data := map[string]any{
+		"password_hash": "secret-hash",
+		"user_id":       "45b5fbd3-755f-4379-8f07-a58d4a30fa2f",
+	}
+
+	err := database.NamedExecContext(ctx, zap.NewNop().Sugar(), db, q, data)
+	if !errors.Is(err, errConnRefused) {
+		t.Fatalf("exec error = %v, want %v", err, errConnRefused)
+	}
+	parent.End()
+
+	var span tracetest.SpanStub
+	for _, s := range exporter.GetSpans() {
+		if strings.HasPrefix(s.Name, "database.") {
+			span = s
+		}
+	}
+	if span.Name == "" {
+		t.Fatal("database span not found")
+	}
+
+	if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("database span parent = %s, want request span %s", span.Parent.SpanID(), parent.SpanContext().SpanID())
+	}
+
+	var statement string
+	for _, attr := range span.Attributes {
+		if attr.Key == "db.statement" {
+			statement = attr.Value.AsString()
+		}
+	}
+	if statement != q {
+		t.Errorf("db.statement = %q, want %q", statement, q)
+	}
+	if strings.Contains(statement, "secret-hash") {
+		t.Errorf("db.statement must not contain query values: %q", statement)
+	}
+
+	if span.Status.Code != codes.Error {
+		t.Errorf("database span status = %v, want %v", span.Status.Code, codes.Error)
+	}
+	if len(span.Events) == 0 || span.Events[0].Name != "exception" {
+		t.Errorf("database span must record the error, got events %v", span.Events)
+	}
+}
+
+// =============================================================================
+
+var errConnRefused = errors.New("connection refused")
+
+// failingConnector é um driver em que toda operação falha, para exercitar o
+// caminho de erro dos helpers sem um banco de verdade
+type failingConnector struct{}
+
+func (failingConnector) Connect(context.Context) (driver.Conn, error) { return failingConn{}, nil }
+func (failingConnector) Driver() driver.Driver                        { return nil }
+
+type failingConn struct{}
+
+func (failingConn) Prepare(string) (driver.Stmt, error) { return nil, errConnRefused }
+func (failingConn) Close() error                        { return nil }
+func (failingConn) Begin() (driver.Tx, error)           { return nil, errConnRefused }
+
+func (failingConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return nil, errConnRefused
+}
@@ -0,0 +1,50 @@
+package errs
+
+// Códigos genéricos, usados quando o erro não possui um código específico
+var (
+	Internal             = Code{"INTERNAL"}
+	BadRequest           = Code{"BAD_REQUEST"}
+	ValidationFailed     = Code{"VALIDATION_FAILED"}
+	Unauthenticated      = Code{"UNAUTHENTICATED"}
+	Forbidden            = Code{"FORBIDDEN"}
+	NotFound             = Code{"NOT_FOUND"}
+	Conflict             = Code{"CONFLICT"}
+	UnsupportedMediaType = Code{"UNSUPPORTED_MEDIA_TYPE"}
+)
+
+// Códigos de usuários
+var (
+	UserNotFound         = Code{"USER_NOT_FOUND"}
+	EmailTaken           = Code{"EMAIL_TAKEN"}
+	AuthenticationFailed = Code{"AUTHENTICATION_FAILED"}
+	UserInUse            = Code{"USER_IN_USE"}
+)
+
+// Códigos de categorias
+var (
+	CategoryNotFound       = Code{"CATEGORY_NOT_FOUND"}
+	CategoryNameTaken      = Code{"CATEGORY_NAME_TAKEN"}
+	CategoryCycle          = Code{"CATEGORY_CYCLE"}
+	CategoryHasChildren    = Code{"CATEGORY_HAS_CHILDREN"}
+	CategoryParentNotFound = Code{"CATEGORY_PARENT_NOT_FOUND"}
+)
+
+// Códigos de produtos, estoque e vendas
+var (
+	ProductNotFound   = Code{"PRODUCT_NOT_FOUND"}
+	ProductInUse      = Code{"PRODUCT_IN_USE"}
+	ImportFailed      = Code{"IMPORT_FAILED"}
+	InsufficientStock = Code{"INSUFFICIENT_STOCK"}
+	InvalidQuantity   = Code{"INVALID_QUANTITY"}
+	SaleNotFound      = Code{"SALE_NOT_FOUND"}
+)
+
+// Códigos de moedas e câmbio
+var (
+	ExchangeRateNotFound = Code{"EXCHANGE_RATE_NOT_FOUND"}
+	UnknownCurrency      = Code{"UNKNOWN_CURRENCY"}
+	BaseCurrencyRate     = Code{"BASE_CURRENCY_RATE"}
+	CurrencyInUse        = Code{"CURRENCY_IN_USE"}
+	CurrencyMismatch     = Code{"CURRENCY_MISMATCH"}
+	InvalidRate          = Code{"INVALID_RATE"}
+)
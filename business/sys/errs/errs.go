@@ -0,0 +1,61 @@
+// Package errs define o catálogo de erros da aplicação. Cada erro conhecido
+// tem um código estável, como USER_NOT_FOUND, que é enviado aos clientes para
+// que não precisem interpretar as mensagens
+package errs
+
+import (
+	"errors"
+)
+
+// Code é o código de um erro do catálogo
+// Esse padrão é o mais próximo que podemos chegar de enums em go
+type Code struct {
+	name string
+}
+
+// Name retorna o nome do código
+func (c Code) Name() string {
+	return c.name
+}
+
+// MarshalText converte o código para json
+func (c Code) MarshalText() ([]byte, error) {
+	return []byte(c.name), nil
+}
+
+// Equal provê suporte para o pacote go-cmp e testing
+func (c Code) Equal(c2 Code) bool {
+	return c.name == c2.name
+}
+
+// =============================================================================
+
+// Error é um erro do catálogo. Os erros dos pacotes de core são criados com
+// New, de forma que continuam podendo ser comparados com errors.Is e também
+// carregam seu código até a camada web
+type Error struct {
+	Code Code
+	msg  string
+}
+
+// New cria um erro com o código informado
+func New(code Code, msg string) error {
+	return &Error{
+		Code: code,
+		msg:  msg,
+	}
+}
+
+// Error implementa a interface error
+func (e *Error) Error() string {
+	return e.msg
+}
+
+// GetCode retorna o código do primeiro erro do catálogo na cadeia de erros
+func GetCode(err error) (Code, bool) {
+	var e *Error
+	if !errors.As(err, &e) {
+		return Code{}, false
+	}
+	return e.Code, true
+}
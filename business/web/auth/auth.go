@@ -9,15 +9,25 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/open-policy-agent/opa/rego"
 	"github.com/vitoraalmeida/service/business/core/user"
+	"github.com/vitoraalmeida/service/business/sys/errs"
+	"github.com/vitoraalmeida/service/business/web/metrics"
+	"github.com/vitoraalmeida/service/foundation/web"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.uber.org/zap"
 )
 
-// ErrForbidden é retornado quando há problemas de autenticação ou autorização
-var ErrForbidden = errors.New("attempted action is not allowed")
+// ErrForbidden é retornado quando há problemas de autorização
+var ErrForbidden = errs.New(errs.Forbidden, "attempted action is not allowed")
+
+// errPolicyDenied indica que a política foi avaliada e negou o acesso, ao
+// contrário de uma falha ao avaliar a política
+var errPolicyDenied = errors.New("policy denied")
 
 // Claims representa o conjunto de alegações (claims) que são trasmitidos no JWT
 type Claims struct {
@@ -147,7 +157,9 @@ func (a *Auth) Authenticate(ctx context.Context, bearerToken string) (Claims, er
 
 // Authorize tenta autorizar o usuário baseado num determinado papel/responsabilidade
 // comparando com o claims que foi passado no token. Se as roles passadas no
-// claims não forem compatíveis com a role em questão, retorna erro
+// claims não forem compatíveis com a role em questão, retorna um erro que
+// satisfaz errors.Is(err, ErrForbidden). Qualquer outro erro é uma falha ao
+// avaliar a política
 func (a *Auth) Authorize(ctx context.Context, claims Claims, rule string) error {
 	input := map[string]any{
 		"Roles":   claims.Roles,
@@ -156,6 +168,9 @@ func (a *Auth) Authorize(ctx context.Context, claims Claims, rule string) error
 	}
 
 	if err := a.opaPolicyEvaluation(ctx, opaAuthorization, rule, input); err != nil {
+		if errors.Is(err, errPolicyDenied) {
+			return fmt.Errorf("rule[%s]: %w", rule, ErrForbidden)
+		}
 		return fmt.Errorf("rego evaluation failed : %w", err)
 	}
 
@@ -201,7 +216,25 @@ func (a *Auth) publicKeyLookup(kid string) (string, error) {
 
 // opaPolicyEvaluation asks opa to evaulate the token against the specified token
 // policy and public key.
-func (a *Auth) opaPolicyEvaluation(ctx context.Context, opaPolicy string, rule string, input any) error {
+func (a *Auth) opaPolicyEvaluation(ctx context.Context, opaPolicy string, rule string, input any) (err error) {
+	ctx, span := web.AddSpan(ctx, "auth.opaPolicyEvaluation", attribute.String("opa.rule", rule))
+	start := time.Now()
+	defer func() {
+		result := "allowed"
+		switch {
+		case errors.Is(err, errPolicyDenied):
+			result = "denied"
+		case err != nil:
+			result = "error"
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.SetAttributes(attribute.String("opa.result", result))
+		metrics.ObserveOPA(rule, result, time.Since(start))
+
+		span.End()
+	}()
+
 	// rule definida no script, indicamos qual regra queremos
 	// validar
 	query := fmt.Sprintf("x = data.%s.%s", opaPackage, rule)
@@ -244,9 +277,12 @@ func (a *Auth) opaPolicyEvaluation(ctx context.Context, opaPolicy string, rule s
 
 	// os resultados da validação são booleanos
 	result, ok := results[0].Bindings["x"].(bool)
-	if !ok || !result {
+	if !ok {
 		return fmt.Errorf("bindings results[%v] ok[%v]", results, ok)
 	}
+	if !result {
+		return errPolicyDenied
+	}
 
 	return nil
 }
@@ -2,6 +2,9 @@ package auth
 
 import (
 	"context"
+	"errors"
+
+	"github.com/google/uuid"
 )
 
 type ctxKey int
@@ -22,3 +25,21 @@ func GetClaims(ctx context.Context) Claims {
 	}
 	return v
 }
+
+// GetUserID retorna o ID do usuário autenticado a partir do Subject presente
+// nas claims armazenadas no contexto. Sem claims o chamador não está
+// autenticado e o erro é um 401. Com um Subject que não é um ID de usuário o
+// token é válido, mas não identifica um usuário, então o erro é um 403
+func GetUserID(ctx context.Context) (uuid.UUID, error) {
+	claims := GetClaims(ctx)
+	if claims.Subject == "" {
+		return uuid.UUID{}, NewAuthError("no claims")
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.UUID{}, NewForbiddenError(claims, "subject", errors.New("subject is not a user id"))
+	}
+
+	return userID, nil
+}
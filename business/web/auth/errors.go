@@ -6,26 +6,73 @@ import (
 )
 
 // AuthError é usado para passar erros durante a requisição que estejam envolvidos
-// com o context de autenticação
+// com o context de autenticação. Distingue falhas de autenticação, quando não
+// sabemos quem é o cliente, de falhas de autorização, quando o cliente
+// autenticado não pode realizar a ação
 type AuthError struct {
-	msg string
+	msg       string
+	forbidden bool
+
+	// preenchidos apenas em falhas de autorização, para auditoria
+	Rule    string
+	Subject string
+	Roles   []string
 }
 
-// NewAuthError cria um AuthError com a mensagem definida
+// NewAuthError cria um AuthError de autenticação com a mensagem definida
 func NewAuthError(format string, args ...any) error {
 	return &AuthError{
 		msg: fmt.Sprintf(format, args...),
 	}
 }
 
+// NewForbiddenError cria um AuthError de autorização, registrando quem teve
+// o acesso negado e por qual regra
+func NewForbiddenError(claims Claims, rule string, err error) error {
+	roles := make([]string, len(claims.Roles))
+	for i, role := range claims.Roles {
+		roles[i] = role.Name()
+	}
+
+	return &AuthError{
+		msg:       fmt.Sprintf("authorize: you are not authorized for that action, claims[%v] rule[%v]: %s", roles, rule, err),
+		forbidden: true,
+		Rule:      rule,
+		Subject:   claims.Subject,
+		Roles:     roles,
+	}
+}
+
 // Error implementa a interface error. Usa a mensagem padrão do erro envolvido.
 // é o que será mostrado nos logs
 func (ae *AuthError) Error() string {
 	return ae.msg
 }
 
+// Forbidden informa se o erro é uma falha de autorização
+func (ae *AuthError) Forbidden() bool {
+	return ae.forbidden
+}
+
+// Unwrap permite verificar falhas de autorização com errors.Is(err, ErrForbidden)
+func (ae *AuthError) Unwrap() error {
+	if ae.forbidden {
+		return ErrForbidden
+	}
+	return nil
+}
+
 // IsAuthError checa se um erro é do tipo AuthError
 func IsAuthError(err error) bool {
 	var ae *AuthError
 	return errors.As(err, &ae)
 }
+
+// GetAuthError retorna uma cópia do ponteiro do AuthError
+func GetAuthError(err error) *AuthError {
+	var ae *AuthError
+	if !errors.As(err, &ae) {
+		return nil
+	}
+	return ae
+}
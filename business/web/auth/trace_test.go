@@ -0,0 +1,76 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/vitoraalmeida/service/business/core/user"
+	"github.com/vitoraalmeida/service/business/web/auth"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+)
+
+func TestPolicyEvaluationSpan(t *testing.T) {
+	a, err := auth.New(auth.Config{Log: zap.NewNop().Sugar()})
+	if err != nil {
+		t.Fatalf("constructing auth: %s", err)
+	}
+
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "45b5fbd3-755f-4379-8f07-a58d4a30fa2f"},
+		Roles:            []user.Role{user.RoleUser},
+	}
+
+	tests := []struct {
+		name          string
+		rule          string
+		wantErr       bool
+		wantForbidden bool
+		wantRecorded  bool
+	}{
+		{name: "allowed", rule: auth.RuleAny},
+		{name: "denied", rule: auth.RuleAdminOnly, wantErr: true, wantForbidden: true},
+		{name: "unknown rule", rule: "ruleUnknown", wantErr: true, wantRecorded: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := tracetest.NewInMemoryExporter()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+			ctx, parent := tp.Tracer("test").Start(context.Background(), "request")
+			err := a.Authorize(ctx, claims, tt.rule)
+			parent.End()
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("authorize error = %v, want error %t", err, tt.wantErr)
+			}
+			if errors.Is(err, auth.ErrForbidden) != tt.wantForbidden {
+				t.Fatalf("authorize error = %v, want forbidden %t", err, tt.wantForbidden)
+			}
+
+			var span tracetest.SpanStub
+			for _, s := range exporter.GetSpans() {
+				if s.Name == "auth.opaPolicyEvaluation" {
+					span = s
+				}
+			}
+			if span.Name == "" {
+				t.Fatal("policy evaluation span not found")
+			}
+
+			if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+				t.Errorf("policy span parent = %s, want request span %s", span.Parent.SpanID(), parent.SpanContext().SpanID())
+			}
+
+			recorded := span.Status.Code == codes.Error && len(span.Events) > 0 && span.Events[0].Name == "exception"
+			if recorded != tt.wantRecorded {
+				t.Errorf("policy span error recorded = %t, want %t (status %v, events %v)", recorded, tt.wantRecorded, span.Status, span.Events)
+			}
+		})
+	}
+}
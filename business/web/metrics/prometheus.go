@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	database "github.com/vitoraalmeida/service/business/sys/database/pgx"
+)
+
+// registry é o registro próprio das métricas expostas no formato do
+// Prometheus. Não usamos o registro global para que bibliotecas de terceiros
+// não adicionem métricas sem sabermos, o mesmo motivo de não usarmos o
+// DefaultServerMux
+var registry = prometheus.NewRegistry()
+
+var (
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "sales",
+		Name:      "http_requests_total",
+		Help:      "Total de requisições atendidas.",
+	}, []string{"route", "method", "status"})
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "sales",
+		Name:      "http_request_duration_seconds",
+		Help:      "Duração das requisições.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	opaDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "sales",
+		Name:      "opa_evaluation_duration_seconds",
+		Help:      "Duração das avaliações de políticas do OPA.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25},
+	}, []string{"rule", "result"})
+)
+
+func init() {
+	registry.MustRegister(
+		requestsTotal,
+		requestDuration,
+		opaDuration,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler retorna o handler que expõe as métricas no formato do Prometheus
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// RegisterDB passa a expor as estatísticas do pool de conexões do banco
+// (conexões abertas, em uso, esperas etc) e as métricas das queries feitas
+// pelo pacote database. Registrar o mesmo banco mais de uma vez não tem efeito
+func RegisterDB(db *sql.DB, name string) error {
+	cs := append(database.Collectors(), collectors.NewDBStatsCollector(db, name))
+
+	for _, c := range cs {
+		err := registry.Register(c)
+
+		var are prometheus.AlreadyRegisteredError
+		if err != nil && !errors.As(err, &are) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ObserveRequest registra uma requisição atendida. route é o padrão da rota,
+// como /users/:user_id, para que a quantidade de séries não dependa dos IDs
+func ObserveRequest(route string, method string, statusCode int, duration time.Duration) {
+	status := strconv.Itoa(statusCode)
+
+	requestsTotal.WithLabelValues(route, method, status).Inc()
+	requestDuration.WithLabelValues(route, method, status).Observe(duration.Seconds())
+}
+
+// ObserveOPA registra a duração da avaliação de uma regra do OPA. result é
+// allowed, denied ou error, quando a política não pôde ser avaliada
+func ObserveOPA(rule string, result string, duration time.Duration) {
+	opaDuration.WithLabelValues(rule, result).Observe(duration.Seconds())
+}
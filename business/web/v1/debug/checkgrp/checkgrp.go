@@ -9,15 +9,17 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/vitoraalmeida/service/business/cview/user/summary"
 	database "github.com/vitoraalmeida/service/business/sys/database/pgx"
 	"go.uber.org/zap"
 )
 
 // Handlers gerencia o conjuntos de handlers de health check
 type Handlers struct {
-	Build string
-	Log   *zap.SugaredLogger
-	DB    *sqlx.DB
+	Build   string
+	Log     *zap.SugaredLogger
+	DB      *sqlx.DB
+	Summary *summary.RefreshWorker // nulo quando o resumo não é materializado
 }
 
 // Readiness vai checar se o banco de dados está pronto e caso contrário
@@ -45,7 +47,13 @@ func (h Handlers) Readiness(w http.ResponseWriter, r *http.Request) {
 		h.Log.Errorw("readiness", "ERROR", err)
 	}
 
-	h.Log.Infow("readiness", "statusCode", statusCode, "method", r.Method, "path", r.URL.Path, "remoteaddr", r.RemoteAddr)
+	// as verificações são feitas a cada poucos segundos pelo orquestrador,
+	// então só as que falharam são registradas fora do nível de debug
+	if statusCode != http.StatusOK {
+		h.Log.Warnw("readiness", "statusCode", statusCode, "method", r.Method, "path", r.URL.Path, "remoteaddr", r.RemoteAddr)
+		return
+	}
+	h.Log.Debugw("readiness", "statusCode", statusCode, "method", r.Method, "path", r.URL.Path, "remoteaddr", r.RemoteAddr)
 }
 
 // Liveness retorna informações simples se o serviço estiver em execução.
@@ -85,7 +93,48 @@ func (h Handlers) Liveness(w http.ResponseWriter, r *http.Request) {
 
 	// THIS IS A FREE TIMER. WE COULD UPDATE THE METRIC GOROUTINE COUNT HERE.
 
-	h.Log.Infow("liveness", "statusCode", statusCode, "method", r.Method, "path", r.URL.Path, "remoteaddr", r.RemoteAddr)
+	h.Log.Debugw("liveness", "statusCode", statusCode, "method", r.Method, "path", r.URL.Path, "remoteaddr", r.RemoteAddr)
+}
+
+// SummaryRefresh retorna o estado da atualização periódica do resumo de
+// usuários materializado
+func (h Handlers) SummaryRefresh(w http.ResponseWriter, r *http.Request) {
+	data := struct {
+		Materialized  bool   `json:"materialized"`
+		Running       bool   `json:"running"`
+		Refreshing    bool   `json:"refreshing"`
+		Interval      string `json:"interval,omitempty"`
+		Refreshes     int    `json:"refreshes"`
+		Failures      int    `json:"failures"`
+		LastRefresh   string `json:"lastRefresh,omitempty"`
+		LastDuration  string `json:"lastDuration,omitempty"`
+		LastError     string `json:"lastError,omitempty"`
+		LastErrorTime string `json:"lastErrorTime,omitempty"`
+	}{}
+
+	if h.Summary != nil {
+		status := h.Summary.Status()
+
+		data.Materialized = true
+		data.Running = status.Running
+		data.Refreshing = status.Refreshing
+		data.Interval = status.Interval.String()
+		data.Refreshes = status.Refreshes
+		data.Failures = status.Failures
+		data.LastError = status.LastError
+		if !status.LastRefresh.IsZero() {
+			data.LastRefresh = status.LastRefresh.UTC().Format(time.RFC3339)
+			data.LastDuration = status.LastDuration.String()
+		}
+		if !status.LastErrorTime.IsZero() {
+			data.LastErrorTime = status.LastErrorTime.UTC().Format(time.RFC3339)
+		}
+	}
+
+	statusCode := http.StatusOK
+	if err := response(w, statusCode, data); err != nil {
+		h.Log.Errorw("summary refresh", "ERROR", err)
+	}
 }
 
 func response(w http.ResponseWriter, statusCode int, data any) error {
@@ -7,7 +7,11 @@ import (
 	"net/http/pprof"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/vitoraalmeida/service/business/cview/user/summary"
+	"github.com/vitoraalmeida/service/business/web/metrics"
 	"github.com/vitoraalmeida/service/business/web/v1/debug/checkgrp"
+	"github.com/vitoraalmeida/service/business/web/v1/debug/loggrp"
+	"github.com/vitoraalmeida/service/foundation/logger"
 	"go.uber.org/zap"
 )
 
@@ -29,17 +33,33 @@ func StandardLibraryMux() *http.ServeMux {
 }
 
 // Adiciona os endpoints personalizados para readiness e liveness no mux
-// que adicionanmos as infos de debug da stdlib
-func Mux(build string, log *zap.SugaredLogger, db *sqlx.DB) http.Handler {
+// que adicionanmos as infos de debug da stdlib, além do controle do nível dos
+// logs
+// smmWorker é nulo quando o resumo de usuários não é materializado
+func Mux(build string, log *zap.SugaredLogger, level *logger.Level, db *sqlx.DB, smmWorker *summary.RefreshWorker) http.Handler {
 	mux := StandardLibraryMux()
 
 	cgh := checkgrp.Handlers{
-		Build: build,
-		Log:   log,
-		DB:    db,
+		Build:   build,
+		Log:     log,
+		DB:      db,
+		Summary: smmWorker,
 	}
 	mux.HandleFunc("/debug/readiness", cgh.Readiness)
 	mux.HandleFunc("/debug/liveness", cgh.Liveness)
+	mux.HandleFunc("/debug/summary", cgh.SummaryRefresh)
+
+	lgh := loggrp.Handlers{
+		Log:   log,
+		Level: level,
+	}
+	mux.HandleFunc("/debug/loglevel", lgh.LogLevel)
+
+	// métricas no formato do Prometheus, incluindo as do pool de conexões
+	if err := metrics.RegisterDB(db.DB, "postgres"); err != nil {
+		log.Errorw("debug", "status", "register db metrics", "ERROR", err)
+	}
+	mux.Handle("/metrics", metrics.Handler())
 
 	return mux
 }
@@ -0,0 +1,146 @@
+// Package loggrp agrupa handlers para alterar o nível do logger com a
+// aplicação em execução
+package loggrp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vitoraalmeida/service/foundation/logger"
+	"go.uber.org/zap"
+)
+
+// maxDuration limita o tempo de um nível temporário, para que um nível de
+// debug esquecido não fique ativo indefinidamente
+const maxDuration = 24 * time.Hour
+
+// Handlers gerencia o conjunto de handlers do nível do logger
+type Handlers struct {
+	Log   *zap.SugaredLogger
+	Level *logger.Level
+}
+
+// LogLevel retorna (GET) ou altera (PUT) o nível do logger. No PUT, level altera
+// o nível base, por tempo indeterminado ou durante duration, e overrides
+// define o nível de pacotes específicos. Um override vazio remove o nível do
+// pacote
+//
+//	{"level": "debug", "duration": "15m", "overrides": {"userdb": "debug"}}
+func (h Handlers) LogLevel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		if err := h.update(r); err != nil {
+			h.respond(w, http.StatusBadRequest, struct {
+				Error string `json:"error"`
+			}{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		h.Log.Infow("loglevel", "status", "log level changed", "level", h.Level.Status().Level, "remoteaddr", r.RemoteAddr)
+
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	status := h.Level.Status()
+
+	data := struct {
+		Level     string            `json:"level"`
+		Overrides map[string]string `json:"overrides"`
+		RevertTo  string            `json:"revertTo,omitempty"`
+		RevertAt  string            `json:"revertAt,omitempty"`
+	}{
+		Level:     status.Level,
+		Overrides: status.Overrides,
+		RevertTo:  status.RevertTo,
+	}
+	if !status.RevertAt.IsZero() {
+		data.RevertAt = status.RevertAt.UTC().Format(time.RFC3339)
+	}
+
+	h.respond(w, http.StatusOK, data)
+}
+
+// update aplica as alterações pedidas. Tudo é validado antes de qualquer
+// alteração, para que um pedido inválido não seja aplicado pela metade
+func (h Handlers) update(r *http.Request) error {
+	var req struct {
+		Level     string            `json:"level"`
+		Duration  string            `json:"duration"`
+		Overrides map[string]string `json:"overrides"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return fmt.Errorf("unable to decode payload: %w", err)
+	}
+
+	if req.Duration != "" && req.Level == "" {
+		return fmt.Errorf("duration requires a level")
+	}
+
+	lvl, err := logger.ParseLevel(req.Level)
+	if req.Level != "" && err != nil {
+		return err
+	}
+
+	var duration time.Duration
+	if req.Duration != "" {
+		duration, err = time.ParseDuration(req.Duration)
+		if err != nil || duration <= 0 || duration > maxDuration {
+			return fmt.Errorf("invalid duration %q, must be between 0 and %s", req.Duration, maxDuration)
+		}
+	}
+
+	for pkg, name := range req.Overrides {
+		if pkg == "" {
+			return fmt.Errorf("override package must not be empty")
+		}
+		if name == "" {
+			continue
+		}
+		if _, err := logger.ParseLevel(name); err != nil {
+			return fmt.Errorf("override %s: %w", pkg, err)
+		}
+	}
+
+	switch {
+	case req.Level == "":
+	case duration > 0:
+		h.Level.SetLevelFor(lvl, duration)
+	default:
+		h.Level.SetLevel(lvl)
+	}
+
+	for pkg, name := range req.Overrides {
+		if name == "" {
+			h.Level.RemoveOverride(pkg)
+			continue
+		}
+		lvl, _ := logger.ParseLevel(name)
+		h.Level.SetOverride(pkg, lvl)
+	}
+
+	return nil
+}
+
+func (h Handlers) respond(w http.ResponseWriter, statusCode int, data any) {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		h.Log.Errorw("loglevel", "ERROR", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if _, err := w.Write(jsonData); err != nil {
+		h.Log.Errorw("loglevel", "ERROR", err)
+	}
+}
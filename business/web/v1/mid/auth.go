@@ -2,6 +2,8 @@ package mid
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/vitoraalmeida/service/business/web/auth"
@@ -33,13 +35,20 @@ func Authenticate(a *auth.Auth) web.Middleware {
 func Authorize(a *auth.Auth, rule string) web.Middleware {
 	m := func(handler web.Handler) web.Handler {
 		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			// sem claims o cliente não está autenticado. Com claims, a negação
+			// é uma falha de autorização
 			claims := auth.GetClaims(ctx)
 			if claims.Subject == "" {
 				return auth.NewAuthError("authorize: you are not authorized for that action, no claims")
 			}
 
+			// apenas a negação da política é uma falha de autorização. Um
+			// erro ao avaliar a política é um erro do servidor
 			if err := a.Authorize(ctx, claims, rule); err != nil {
-				return auth.NewAuthError("authorize: you are not authorized for that action, claims[%v] rule[%v]: %s", claims.Roles, rule, err)
+				if errors.Is(err, auth.ErrForbidden) {
+					return auth.NewForbiddenError(claims, rule, err)
+				}
+				return fmt.Errorf("authorize: rule[%s]: %w", rule, err)
 			}
 
 			return handler(ctx, w, r)
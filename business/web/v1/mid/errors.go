@@ -2,8 +2,11 @@ package mid
 
 import (
 	"context"
+	"mime"
 	"net/http"
+	"strings"
 
+	"github.com/vitoraalmeida/service/business/sys/errs"
 	"github.com/vitoraalmeida/service/business/sys/validate"
 	"github.com/vitoraalmeida/service/business/web/auth"
 	v1 "github.com/vitoraalmeida/service/business/web/v1"
@@ -22,44 +25,27 @@ func Errors(log *zap.SugaredLogger) web.Middleware {
 			if err := handler(ctx, w, r); err != nil {
 				log.Errorw("ERROR", "trace_id", web.GetTraceID(ctx), "message", err)
 
-				var er v1.ErrorResponse
-				var status int
-
-				// Se for um erro conhecido, buscamos esse erro e criamos um
-				// erro de resposta e definimos o status code da resposta
-				switch {
-				// erros de validação
-				case validate.IsFieldErrors(err):
-					fieldErrors := validate.GetFieldErrors(err)
-					er = v1.ErrorResponse{
-						Error:  "data validation error",
-						Fields: fieldErrors.Fields(),
-					}
-					status = http.StatusBadRequest
-				case v1.IsRequestError(err):
-					reqErr := v1.GetRequestError(err)
-					er = v1.ErrorResponse{
-						Error: reqErr.Error(),
-					}
-					status = reqErr.Status
-
-				case auth.IsAuthError(err):
-					er = v1.ErrorResponse{
-						Error: http.StatusText(http.StatusUnauthorized),
-					}
-					status = http.StatusUnauthorized
-
-				// se for um erro inesperado, criamos o erro de resposta como
-				// internalServerError (500)
-				default:
-					er = v1.ErrorResponse{
-						Error: http.StatusText(http.StatusInternalServerError),
-					}
-					status = http.StatusInternalServerError
+				// a resposta já começou a ser enviada, não há como informar
+				// o erro ao cliente
+				if web.IsStreamError(err) {
+					return nil
 				}
 
-				// enviamos a resposta de erro
-				if err := web.Respond(ctx, w, er, status); err != nil {
+				// negações de acesso são registradas para auditoria
+				if ae := auth.GetAuthError(err); ae != nil && ae.Forbidden() {
+					audit(ctx, log, r, ae)
+				}
+
+				er, status := errorResponse(err)
+				er.TraceID = web.GetTraceID(ctx)
+
+				if status == http.StatusUnauthorized {
+					w.Header().Set("WWW-Authenticate", authenticateChallenge(r))
+				}
+
+				// enviamos a resposta de erro, no formato da RFC 7807 se o
+				// cliente o pediu
+				if err := respondError(ctx, w, r, er, status); err != nil {
 					return err
 				}
 
@@ -80,3 +66,101 @@ func Errors(log *zap.SugaredLogger) web.Middleware {
 
 	return m
 }
+
+// errorResponse converte o erro na resposta que será enviada ao cliente e no
+// status code correspondente.
+// Se for um erro conhecido, buscamos esse erro e criamos um erro de resposta
+// e definimos o status code da resposta
+func errorResponse(err error) (v1.ErrorResponse, int) {
+	switch {
+	// erros de validação
+	case validate.IsFieldErrors(err):
+		fieldErrors := validate.GetFieldErrors(err)
+		return v1.ErrorResponse{
+			Code:   errs.ValidationFailed.Name(),
+			Error:  "data validation error",
+			Fields: fieldErrors.Fields(),
+		}, http.StatusBadRequest
+
+	case v1.IsRequestError(err):
+		reqErr := v1.GetRequestError(err)
+		return v1.ErrorResponse{
+			Code:  reqErr.Code.Name(),
+			Error: reqErr.Error(),
+		}, reqErr.Status
+
+	// o cliente autenticado não tem permissão para a ação
+	case auth.IsAuthError(err) && auth.GetAuthError(err).Forbidden():
+		return v1.ErrorResponse{
+			Code:  errs.Forbidden.Name(),
+			Error: http.StatusText(http.StatusForbidden),
+		}, http.StatusForbidden
+
+	case auth.IsAuthError(err):
+		return v1.ErrorResponse{
+			Code:  errs.Unauthenticated.Name(),
+			Error: http.StatusText(http.StatusUnauthorized),
+		}, http.StatusUnauthorized
+
+	// se for um erro inesperado, criamos o erro de resposta como
+	// internalServerError (500)
+	default:
+		return v1.ErrorResponse{
+			Code:  errs.Internal.Name(),
+			Error: http.StatusText(http.StatusInternalServerError),
+		}, http.StatusInternalServerError
+	}
+}
+
+// respondError envia a resposta de erro. Clientes que aceitam
+// application/problem+json recebem o erro no formato da RFC 7807
+func respondError(ctx context.Context, w http.ResponseWriter, r *http.Request, er v1.ErrorResponse, status int) error {
+	if !acceptsProblem(r) {
+		return web.Respond(ctx, w, er, status)
+	}
+
+	return web.RespondContentType(ctx, w, er.Problem(status, r.URL.Path), problemContentType, status)
+}
+
+const problemContentType = "application/problem+json"
+
+// acceptsProblem informa se o cabeçalho Accept pede o formato da RFC 7807
+func acceptsProblem(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, part := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil || mediaType != problemContentType {
+				continue
+			}
+
+			// q=0 indica que o formato não é aceito
+			if q, ok := params["q"]; ok && strings.Trim(q, "0.") == "" {
+				continue
+			}
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// authenticateChallenge retorna o cabeçalho WWW-Authenticate das respostas 401,
+// conforme a RFC 6750. O código invalid_token só é enviado quando o cliente
+// apresentou credenciais
+func authenticateChallenge(r *http.Request) string {
+	if r.Header.Get("Authorization") == "" {
+		return "Bearer"
+	}
+	return `Bearer error="invalid_token"`
+}
+
+// audit registra uma negação de acesso. É registrada em nível Warn para que
+// não seja descartada pela amostragem dos logs
+func audit(ctx context.Context, log *zap.SugaredLogger, r *http.Request, ae *auth.AuthError) {
+	v := web.GetValues(ctx)
+
+	log.Warnw("audit", "event", "authorization denied", "trace_id", v.TraceID, "subject", ae.Subject,
+		"roles", ae.Roles, "rule", ae.Rule, "method", r.Method, "route", v.Route, "path", r.URL.Path,
+		"remoteaddr", r.RemoteAddr)
+}
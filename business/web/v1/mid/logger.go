@@ -10,9 +10,26 @@ import (
 	"go.uber.org/zap"
 )
 
+// LoggerConfig define quais requisições são registradas pelo middleware de logs
+type LoggerConfig struct {
+	// SlowRequest é a duração a partir da qual a requisição é registrada em
+	// nível Warn, que não passa pela amostragem. Zero desativa o aviso
+	SlowRequest time.Duration
+
+	// SkipRoutes são padrões de rota, como /users/:user_id, que não têm suas
+	// requisições registradas, exceto as lentas e as que falharam com erro do
+	// servidor
+	SkipRoutes []string
+}
+
 // Logger adiciona a capacidade de realizar logging antes e depois do processamento
 // de uma requisição
-func Logger(log *zap.SugaredLogger) web.Middleware {
+func Logger(log *zap.SugaredLogger, cfg LoggerConfig) web.Middleware {
+	skip := make(map[string]bool, len(cfg.SkipRoutes))
+	for _, route := range cfg.SkipRoutes {
+		skip[route] = true
+	}
+
 	// cria o middleware no formato esperado pelo mux (implementa handleFunc)
 	m := func(handler web.Handler) web.Handler {
 		// cria o handler que é executado pelo nosso framework
@@ -25,15 +42,35 @@ func Logger(log *zap.SugaredLogger) web.Middleware {
 				path = fmt.Sprintf("%s?%s", path, r.URL.RawQuery)
 			}
 
-			log.Infow("request started", "trace_id", v.TraceID, "method", r.Method, "path", path,
-				"remoteaddr", r.RemoteAddr)
+			quiet := skip[v.Route]
+
+			if !quiet {
+				log.Infow("request started", "trace_id", v.TraceID, "method", r.Method, "route", v.Route, "path", path,
+					"remoteaddr", r.RemoteAddr)
+			}
 
 			// chama a função que o mid de logger engloba
 			err := handler(ctx, w, r)
 
-			// loga o fim do request
-			log.Infow("request completed", "trace_id", v.TraceID, "method", r.Method, "path", path,
-				"remoteaddr", r.RemoteAddr, "statuscode", v.StatusCode, "since", time.Since(v.Now))
+			// loga o fim do request. Requisições lentas e as que falharam com
+			// erro do servidor são registradas como aviso para que apareçam
+			// mesmo com a amostragem dos logs
+			since := time.Since(v.Now)
+			slow := cfg.SlowRequest > 0 && since >= cfg.SlowRequest
+
+			switch {
+			case slow:
+				log.Warnw("request completed", "trace_id", v.TraceID, "method", r.Method, "route", v.Route, "path", path,
+					"remoteaddr", r.RemoteAddr, "statuscode", v.StatusCode, "since", since, "slow", true)
+
+			case v.StatusCode >= http.StatusInternalServerError:
+				log.Warnw("request completed", "trace_id", v.TraceID, "method", r.Method, "route", v.Route, "path", path,
+					"remoteaddr", r.RemoteAddr, "statuscode", v.StatusCode, "since", since)
+
+			case !quiet:
+				log.Infow("request completed", "trace_id", v.TraceID, "method", r.Method, "route", v.Route, "path", path,
+					"remoteaddr", r.RemoteAddr, "statuscode", v.StatusCode, "since", since)
+			}
 
 			// retorna o erro para ser tratado por quem deve tratar
 			return err
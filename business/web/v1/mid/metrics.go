@@ -3,12 +3,14 @@ package mid
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/vitoraalmeida/service/business/web/metrics"
 	"github.com/vitoraalmeida/service/foundation/web"
 )
 
-// Metrics atualiza os contadores de metricas
+// Metrics atualiza os contadores de metricas e registra a duração de cada
+// requisição por rota, método e status
 func Metrics() web.Middleware {
 	m := func(handler web.Handler) web.Handler {
 		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
@@ -16,6 +18,15 @@ func Metrics() web.Middleware {
 
 			err := handler(ctx, w, r)
 
+			// o middleware de erros ainda não respondeu, então o status é o
+			// que ele vai usar para o erro
+			v := web.GetValues(ctx)
+			status := v.StatusCode
+			if err != nil && !web.IsStreamError(err) {
+				_, status = errorResponse(err)
+			}
+			metrics.ObserveRequest(v.Route, r.Method, status, time.Since(v.Now))
+
 			metrics.AddRequests(ctx)
 			// cada requisição é executada em uma goroutine diferente
 			metrics.AddGoroutines(ctx)
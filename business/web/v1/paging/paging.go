@@ -2,32 +2,104 @@
 package paging
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 
+	"github.com/vitoraalmeida/service/business/data/cursor"
+	"github.com/vitoraalmeida/service/business/data/order"
 	"github.com/vitoraalmeida/service/business/sys/validate"
 )
 
 // Response é o retorno quando um query é executada
 type Response[T any] struct {
-	Items       []T `json:"items"`
-	Total       int `json:"total"`
-	Page        int `json:"page"`
-	RowsPerPage int `json:"rowsPerPage"`
+	Items       []T  `json:"items"`
+	Total       int  `json:"total"`
+	Page        int  `json:"page"`
+	RowsPerPage int  `json:"rowsPerPage"`
+	TotalPages  int  `json:"totalPages"`
+	HasNext     bool `json:"hasNext"`
+	HasPrev     bool `json:"hasPrev"`
 }
 
 // NewResponse constrói uma resposta paginada
 func NewResponse[T any](items []T, total int, page int, rowsPrePage int) Response[T] {
+	var totalPages int
+	if rowsPrePage > 0 {
+		totalPages = (total + rowsPrePage - 1) / rowsPrePage
+	}
+
 	return Response[T]{
 		Items:       items,
 		Total:       total,
 		Page:        page,
 		RowsPerPage: rowsPrePage,
+		TotalPages:  totalPages,
+		HasNext:     page < totalPages,
+		HasPrev:     page > 1,
+	}
+}
+
+// CursorResponse é o retorno quando uma query é executada no modo de
+// paginação por cursor
+type CursorResponse[T any] struct {
+	Items       []T    `json:"items"`
+	NextCursor  string `json:"nextCursor,omitempty"`
+	PrevCursor  string `json:"prevCursor,omitempty"`
+	RowsPerPage int    `json:"rowsPerPage"`
+}
+
+// NewCursorResponse constrói uma resposta paginada por cursor
+func NewCursorResponse[T any](items []T, page cursor.Page, rowsPerPage int) CursorResponse[T] {
+	return CursorResponse[T]{
+		Items:       items,
+		NextCursor:  page.Next,
+		PrevCursor:  page.Prev,
+		RowsPerPage: rowsPerPage,
 	}
 }
 
 // =============================================================================
 
+// Config define os limites de paginação aceitos por uma rota. Cada grupo de
+// handlers recebe a sua, de forma que rotas diferentes podem ter limites
+// diferentes
+type Config struct {
+	DefaultRows int // quantidade de linhas quando o cliente não informa
+	MinRows     int
+	MaxRows     int
+}
+
+// DefaultConfig são os limites usados quando a rota não define os seus
+var DefaultConfig = Config{
+	DefaultRows: 10,
+	MinRows:     1,
+	MaxRows:     100,
+}
+
+// withDefaults completa os campos não definidos com os valores de DefaultConfig
+func (cfg Config) withDefaults() Config {
+	if cfg.MinRows <= 0 {
+		cfg.MinRows = DefaultConfig.MinRows
+	}
+	if cfg.MaxRows <= 0 {
+		cfg.MaxRows = DefaultConfig.MaxRows
+	}
+	if cfg.DefaultRows <= 0 {
+		cfg.DefaultRows = min(max(DefaultConfig.DefaultRows, cfg.MinRows), cfg.MaxRows)
+	}
+	return cfg
+}
+
+// =============================================================================
+
+// MaxOffset limita quantas linhas podem ser puladas com OFFSET. Além de manter
+// (page-1)*rows longe do overflow, páginas muito profundas ficam lentas e
+// devem ser lidas com paginação por cursor
+const MaxOffset = 1_000_000
+
 // Page representa a página e e linhas da paǵina
 type Page struct {
 	Number      int
@@ -35,8 +107,8 @@ type Page struct {
 }
 
 // ParseRequest faz o parse do request recuperando as informações passadas
-// relativas à paginação desejada pelo usuário
-func ParseRequest(r *http.Request) (Page, error) {
+// relativas à paginação desejada pelo usuário, dentro dos limites de cfg
+func ParseRequest(r *http.Request, cfg Config) (Page, error) {
 	values := r.URL.Query()
 
 	number := 1
@@ -48,17 +120,87 @@ func ParseRequest(r *http.Request) (Page, error) {
 		}
 	}
 
-	rowsPerPage := 10
+	if number < 1 {
+		return Page{}, validate.NewFieldsError("page", errors.New("must be greater than or equal to 1"))
+	}
+
+	rowsPerPage, err := parseRows(values, cfg)
+	if err != nil {
+		return Page{}, err
+	}
+
+	// a comparação por divisão não sofre overflow mesmo com page enorme
+	if number-1 > MaxOffset/rowsPerPage {
+		return Page{}, validate.NewFieldsError("page", fmt.Errorf("offset must not exceed %d rows, use cursor pagination", MaxOffset))
+	}
+
+	return Page{
+		Number:      number,
+		RowsPerPage: rowsPerPage,
+	}, nil
+}
+
+// =============================================================================
+
+// CursorPage representa o cursor e as linhas da página no modo de paginação
+// por cursor
+type CursorPage struct {
+	Cursor      cursor.Cursor
+	RowsPerPage int
+}
+
+// IsCursorRequest informa se o cliente pediu paginação por cursor. A presença
+// do parâmetro "cursor", mesmo vazio, ativa esse modo; vazio representa a
+// primeira página
+func IsCursorRequest(r *http.Request) bool {
+	return r.URL.Query().Has("cursor")
+}
+
+// ParseCursorRequest faz o parse do cursor e da quantidade de linhas pedidas.
+// Um cursor só é válido para a mesma ordenação que o gerou
+func ParseCursorRequest(r *http.Request, orderBy order.By, cfg Config) (CursorPage, error) {
+	values := r.URL.Query()
+
+	cur, err := cursor.Decode(values.Get("cursor"))
+	if err != nil {
+		return CursorPage{}, validate.NewFieldsError("cursor", err)
+	}
+
+	if !cur.IsZero() && cur.Order != orderBy.String() {
+		return CursorPage{}, validate.NewFieldsError("cursor", errors.New("cursor does not match the requested order"))
+	}
+
+	rowsPerPage, err := parseRows(values, cfg)
+	if err != nil {
+		return CursorPage{}, err
+	}
+
+	return CursorPage{
+		Cursor:      cur,
+		RowsPerPage: rowsPerPage,
+	}, nil
+}
+
+// =============================================================================
+
+// parseRows recupera a quantidade de linhas por página, rejeitando valores
+// fora dos limites para que uma única requisição não consiga trazer a tabela
+// inteira
+func parseRows(values url.Values, cfg Config) (int, error) {
+	cfg = cfg.withDefaults()
+
+	rowsPerPage := cfg.DefaultRows
 	if rows := values.Get("rows"); rows != "" {
 		var err error
 		rowsPerPage, err = strconv.Atoi(rows)
 		if err != nil {
-			return Page{}, validate.NewFieldsError("rows", err)
+			return 0, validate.NewFieldsError("rows", err)
 		}
 	}
 
-	return Page{
-		Number:      number,
-		RowsPerPage: rowsPerPage,
-	}, nil
+	if rowsPerPage < cfg.MinRows || rowsPerPage > cfg.MaxRows {
+		return 0, validate.NewFieldsError("rows", fmt.Errorf("must be between %d and %d", cfg.MinRows, cfg.MaxRows))
+	}
+
+	return rowsPerPage, nil
 }
@@ -3,12 +3,50 @@ package v1
 
 import (
 	"errors"
+	"net/http"
+
+	"github.com/vitoraalmeida/service/business/sys/errs"
 )
 
-// ErrorResponse é a forma usada para respostas da API sobre erros na API
+// ErrorResponse é a forma usada para respostas da API sobre erros na API.
+// Code é estável e deve ser usado pelos clientes no lugar da mensagem
 type ErrorResponse struct {
-	Error  string            `json:"error"`
-	Fields map[string]string `json:"fields,omitempty"`
+	Code    string            `json:"code"`
+	Error   string            `json:"error"`
+	Fields  map[string]string `json:"fields,omitempty"`
+	TraceID string            `json:"trace_id,omitempty"` // para o suporte localizar a falha nos logs
+}
+
+// ProblemDetails é a resposta de erro no formato da RFC 7807
+// (application/problem+json), enviada quando o cliente a pede no cabeçalho
+// Accept
+type ProblemDetails struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title"`
+	Status   int               `json:"status"`
+	Detail   string            `json:"detail,omitempty"`
+	Instance string            `json:"instance,omitempty"`
+	Code     string            `json:"code"`
+	Fields   map[string]string `json:"fields,omitempty"`
+	TraceID  string            `json:"trace_id,omitempty"`
+}
+
+// prefixo do tipo dos problemas. O código do erro identifica o tipo
+const problemTypePrefix = "urn:sales-api:error:"
+
+// Problem converte a resposta de erro no formato da RFC 7807. instance é o
+// caminho da requisição que falhou
+func (er ErrorResponse) Problem(status int, instance string) ProblemDetails {
+	return ProblemDetails{
+		Type:     problemTypePrefix + er.Code,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   er.Error,
+		Instance: instance,
+		Code:     er.Code,
+		Fields:   er.Fields,
+		TraceID:  er.TraceID,
+	}
 }
 
 // RequestError é usada para passar o erro durante a requisição através da
@@ -16,12 +54,20 @@ type ErrorResponse struct {
 type RequestError struct {
 	Err    error
 	Status int
+	Code   errs.Code
 }
 
 // NewRequestError recebe um erro e o engloba com um status HTTP
-// Deve ser usada quando handlers encontram erros esperados
+// Deve ser usada quando handlers encontram erros esperados. O código vem do
+// catálogo quando o erro foi criado pelo pacote errs ou, caso contrário, é o
+// código genérico do status
 func NewRequestError(err error, status int) error {
-	return &RequestError{err, status}
+	code, ok := errs.GetCode(err)
+	if !ok {
+		code = statusCode(status)
+	}
+
+	return &RequestError{err, status, code}
 }
 
 // Error implementa a interface error usando a mensagem padrão do erro encontrado.
@@ -44,3 +90,24 @@ func GetRequestError(err error) *RequestError {
 	}
 	return re
 }
+
+// statusCode retorna o código genérico de um status HTTP
+func statusCode(status int) errs.Code {
+	switch status {
+	case http.StatusUnauthorized:
+		return errs.Unauthenticated
+	case http.StatusForbidden:
+		return errs.Forbidden
+	case http.StatusNotFound:
+		return errs.NotFound
+	case http.StatusConflict:
+		return errs.Conflict
+	case http.StatusUnsupportedMediaType:
+		return errs.UnsupportedMediaType
+	}
+
+	if status >= http.StatusInternalServerError {
+		return errs.Internal
+	}
+	return errs.BadRequest
+}
@@ -0,0 +1,229 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Level controla o nível do logger em tempo de execução. Além do nível base,
+// aceita níveis específicos por pacote, definidos pelo caminho de importação
+// do pacote que fez o log (ou o final dele, como userdb ou core/user), e um
+// nível temporário que volta ao anterior depois de um tempo
+type Level struct {
+	base zap.AtomicLevel
+
+	mu        sync.RWMutex
+	overrides map[string]zapcore.Level
+	revert    *time.Timer
+	revertTo  zapcore.Level
+	revertAt  time.Time
+}
+
+// LevelStatus é o estado atual dos níveis do logger
+type LevelStatus struct {
+	Level     string
+	Overrides map[string]string
+	RevertTo  string
+	RevertAt  time.Time // zero quando não há nível temporário
+}
+
+// NewLevel constrói um Level com o nível base informado
+func NewLevel(lvl zapcore.Level) *Level {
+	return &Level{
+		base:      zap.NewAtomicLevelAt(lvl),
+		overrides: make(map[string]zapcore.Level),
+	}
+}
+
+// ParseLevel converte o nome de um nível (debug, info, warn, error) no nível
+// do zap
+func ParseLevel(name string) (zapcore.Level, error) {
+	lvl, err := zapcore.ParseLevel(name)
+	if err != nil {
+		return 0, fmt.Errorf("invalid log level %q", name)
+	}
+	return lvl, nil
+}
+
+// SetLevel altera o nível base, cancelando um nível temporário em andamento
+func (l *Level) SetLevel(lvl zapcore.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stopRevert()
+	l.base.SetLevel(lvl)
+}
+
+// SetLevelFor altera o nível base por um tempo, depois do qual o nível
+// anterior é restaurado. Chamadas seguintes durante esse tempo apenas trocam o
+// nível e o prazo, mantendo o nível que será restaurado
+func (l *Level) SetLevelFor(lvl zapcore.Level, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	revertTo := l.base.Level()
+	if l.revert != nil {
+		revertTo = l.revertTo
+		l.stopRevert()
+	}
+
+	l.base.SetLevel(lvl)
+	l.revertTo = revertTo
+	l.revertAt = time.Now().Add(d)
+
+	var timer *time.Timer
+	timer = time.AfterFunc(d, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		// o timer pode ter sido substituído enquanto esperava pelo lock
+		if l.revert != timer {
+			return
+		}
+
+		l.base.SetLevel(l.revertTo)
+		l.revert = nil
+		l.revertAt = time.Time{}
+	})
+	l.revert = timer
+}
+
+// SetOverride define o nível de um pacote específico
+func (l *Level) SetOverride(pkg string, lvl zapcore.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.overrides[strings.Trim(pkg, "/")] = lvl
+}
+
+// RemoveOverride faz o pacote voltar a usar o nível base
+func (l *Level) RemoveOverride(pkg string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.overrides, strings.Trim(pkg, "/"))
+}
+
+// Status retorna o estado atual dos níveis
+func (l *Level) Status() LevelStatus {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	status := LevelStatus{
+		Level:     l.base.Level().String(),
+		Overrides: make(map[string]string, len(l.overrides)),
+	}
+	for pkg, lvl := range l.overrides {
+		status.Overrides[pkg] = lvl.String()
+	}
+	if l.revert != nil {
+		status.RevertTo = l.revertTo.String()
+		status.RevertAt = l.revertAt
+	}
+
+	return status
+}
+
+// Enabled implementa zapcore.LevelEnabler. Como o pacote de quem fez o log só
+// é conhecido na escrita, habilita o menor nível entre o base e os dos pacotes
+func (l *Level) Enabled(lvl zapcore.Level) bool {
+	if l.base.Enabled(lvl) {
+		return true
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for _, o := range l.overrides {
+		if o.Enabled(lvl) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// enabledFor informa se o log de um nível deve ser escrito para o pacote de
+// quem o fez. O nível do pacote mais específico tem prioridade sobre o base
+func (l *Level) enabledFor(caller zapcore.EntryCaller, lvl zapcore.Level) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if len(l.overrides) == 0 || !caller.Defined {
+		return l.base.Enabled(lvl)
+	}
+
+	pkg := callerPackage(caller.Function)
+
+	keys := make([]string, 0, len(l.overrides))
+	for key := range l.overrides {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return len(keys[i]) > len(keys[j]) })
+
+	for _, key := range keys {
+		if pkg == key || strings.HasSuffix(pkg, "/"+key) {
+			return l.overrides[key].Enabled(lvl)
+		}
+	}
+
+	return l.base.Enabled(lvl)
+}
+
+// stopRevert cancela o nível temporário. Deve ser chamada com o lock
+func (l *Level) stopRevert() {
+	if l.revert == nil {
+		return
+	}
+
+	l.revert.Stop()
+	l.revert = nil
+	l.revertAt = time.Time{}
+}
+
+// callerPackage extrai o caminho do pacote do nome completo da função, como
+// github.com/org/service/business/core/user.(*Core).Create
+func callerPackage(function string) string {
+	slash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[slash+1:], "."); dot >= 0 {
+		return function[:slash+1+dot]
+	}
+	return function
+}
+
+// =============================================================================
+
+// levelCore aplica os níveis por pacote na escrita, quando o caller da
+// entrada já é conhecido
+type levelCore struct {
+	zapcore.Core
+	level *Level
+}
+
+func (c *levelCore) Enabled(lvl zapcore.Level) bool {
+	return c.level.Enabled(lvl)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *levelCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if !c.level.enabledFor(ent.Caller, ent.Level) {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
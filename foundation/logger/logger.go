@@ -8,10 +8,17 @@ import (
 )
 
 // New constrói um Sugared Logger que escreve no stdout e provê timestamps
-// legíveis para humanos
-func New(service string, outputPaths ...string) (*zap.SugaredLogger, error) {
+// legíveis para humanos. O nível é controlado por level e a amostragem por
+// sampler, ambos podendo ser alterados com a aplicação em execução. Sem
+// sampler, todos os logs habilitados são escritos
+func New(service string, level *Level, sampler *Sampler, outputPaths ...string) (*zap.SugaredLogger, error) {
 	config := zap.NewProductionConfig()
 
+	// o nível é decidido pelo levelCore e a amostragem pelo samplingCore,
+	// então o core de escrita aceita tudo
+	config.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
+	config.Sampling = nil
+
 	// formato = "2023-08-30T11:26:10.471-0300"
 	config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 	config.DisableStacktrace = true
@@ -24,7 +31,17 @@ func New(service string, outputPaths ...string) (*zap.SugaredLogger, error) {
 		config.OutputPaths = outputPaths
 	}
 
-	log, err := config.Build(zap.WithCaller(true))
+	wrap := zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		core = &levelCore{Core: core, level: level}
+		if sampler != nil {
+			// a amostragem fica por fora e só conta entradas de níveis
+			// habilitados
+			core = &samplingCore{Core: core, sampler: sampler}
+		}
+		return core
+	})
+
+	log, err := config.Build(zap.WithCaller(true), wrap)
 	if err != nil {
 		return nil, err
 	}
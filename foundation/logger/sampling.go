@@ -0,0 +1,222 @@
+package logger
+
+import (
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// TraceIDKey é o campo que identifica as entradas de uma mesma requisição
+const TraceIDKey = "trace_id"
+
+// quantidade de contadores por nível das entradas sem trace. Mensagens
+// diferentes podem dividir um contador, o que só antecipa a amostragem delas
+const countersPerLevel = 4096
+
+// Sampler limita a quantidade de logs repetidos. Entradas de nível Warn ou
+// maior nunca são descartadas, para que erros e avisos como requisições lentas
+// sempre apareçam.
+//
+// Entradas sem trace_id são amostradas pelo nível e pela mensagem: em cada
+// intervalo de tick, as primeiras Initial são escritas e, a partir daí, apenas
+// uma a cada Thereafter.
+//
+// Entradas com trace_id são amostradas por requisição, para que todas as
+// linhas de uma requisição sejam mantidas ou descartadas juntas. Quando um
+// intervalo passa de Initial entradas com trace, no intervalo seguinte apenas
+// uma a cada Thereafter requisições é mantida, escolhida pelo hash do
+// trace_id. Uma requisição que atravessa a mudança de intervalo pode ficar
+// incompleta
+type Sampler struct {
+	cfg atomic.Pointer[samplingConfig]
+
+	// apenas Debug e Info são amostrados
+	counters [zapcore.WarnLevel - zapcore.DebugLevel][countersPerLevel]counter
+
+	traced       counter     // entradas com trace no intervalo atual
+	tracedActive atomic.Bool // o intervalo anterior passou de Initial entradas com trace
+}
+
+type samplingConfig struct {
+	initial    uint64
+	thereafter uint64
+	tick       time.Duration
+}
+
+// NewSampler constrói um Sampler. Initial zerado desativa a amostragem
+func NewSampler(initial int, thereafter int, tick time.Duration) *Sampler {
+	var s Sampler
+	s.Set(initial, thereafter, tick)
+
+	return &s
+}
+
+// Set altera a configuração da amostragem com a aplicação em execução.
+// Initial zerado desativa a amostragem e Thereafter zerado descarta tudo o que
+// passar de Initial dentro do intervalo
+func (s *Sampler) Set(initial int, thereafter int, tick time.Duration) {
+	if initial < 0 {
+		initial = 0
+	}
+	if thereafter < 0 {
+		thereafter = 0
+	}
+	if tick <= 0 {
+		tick = time.Second
+	}
+
+	s.cfg.Store(&samplingConfig{
+		initial:    uint64(initial),
+		thereafter: uint64(thereafter),
+		tick:       tick,
+	})
+}
+
+// sample informa se a entrada deve ser escrita. traceID é vazio para
+// entradas fora de uma requisição
+func (s *Sampler) sample(ent zapcore.Entry, traceID string) bool {
+	if ent.Level >= zapcore.WarnLevel {
+		return true
+	}
+
+	cfg := s.cfg.Load()
+	if cfg.initial == 0 {
+		return true
+	}
+
+	if traceID != "" {
+		return s.sampleTrace(ent, traceID, cfg)
+	}
+
+	c := &s.counters[ent.Level-zapcore.DebugLevel][hash(ent.Message)%countersPerLevel]
+	n, _ := c.inc(ent.Time, cfg.tick)
+
+	switch {
+	case n <= cfg.initial:
+		return true
+	case cfg.thereafter == 0:
+		return false
+	default:
+		return (n-cfg.initial)%cfg.thereafter == 0
+	}
+}
+
+// sampleTrace decide pela requisição. A decisão depende apenas do trace_id e
+// do volume do intervalo anterior, então é a mesma para todas as linhas da
+// requisição dentro de um intervalo
+func (s *Sampler) sampleTrace(ent zapcore.Entry, traceID string, cfg *samplingConfig) bool {
+	if _, prev := s.traced.inc(ent.Time, cfg.tick); prev >= 0 {
+		s.tracedActive.Store(uint64(prev) > cfg.initial)
+	}
+
+	switch {
+	case !s.tracedActive.Load():
+		return true
+	case cfg.thereafter == 0:
+		return false
+	default:
+		return hash(traceID)%cfg.thereafter == 0
+	}
+}
+
+// hash é usado para distribuir as mensagens entre os contadores e escolher as
+// requisições mantidas. É o FNV-1a de 64 bits, sem alocação
+func hash(s string) uint64 {
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+
+	h := uint64(offset64)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= prime64
+	}
+	return h
+}
+
+// =============================================================================
+
+// counter conta as entradas de um intervalo sem lock, reiniciando a contagem
+// quando o intervalo termina
+type counter struct {
+	resetAt atomic.Int64
+	count   atomic.Uint64
+}
+
+// inc incrementa o contador e retorna a contagem no intervalo atual. Quando a
+// chamada inicia um novo intervalo, retorna também a contagem do intervalo
+// que terminou, e -1 nos demais casos
+func (c *counter) inc(t time.Time, tick time.Duration) (uint64, int64) {
+	now := t.UnixNano()
+
+	resetAt := c.resetAt.Load()
+	if resetAt > now {
+		return c.count.Add(1), -1
+	}
+
+	if !c.resetAt.CompareAndSwap(resetAt, now+tick.Nanoseconds()) {
+		// outra goroutine iniciou o intervalo
+		return c.count.Add(1), -1
+	}
+
+	prev := c.count.Swap(1)
+
+	// sem escritas por mais de um intervalo, o volume anterior é zero
+	if resetAt == 0 || now-resetAt >= tick.Nanoseconds() {
+		prev = 0
+	}
+
+	return 1, int64(prev)
+}
+
+// =============================================================================
+
+// samplingCore descarta as entradas que o Sampler não deixar passar. A
+// decisão é tomada na escrita, quando os campos da entrada, incluindo o
+// trace_id, são conhecidos. Cores derivados com With compartilham o mesmo
+// Sampler e mantêm o trace_id adicionado
+type samplingCore struct {
+	zapcore.Core
+	sampler *Sampler
+	traceID string
+}
+
+func (c *samplingCore) With(fields []zapcore.Field) zapcore.Core {
+	traceID := c.traceID
+	if id, ok := traceIDField(fields); ok {
+		traceID = id
+	}
+
+	return &samplingCore{Core: c.Core.With(fields), sampler: c.sampler, traceID: traceID}
+}
+
+func (c *samplingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+	return ce.AddCore(ent, c)
+}
+
+func (c *samplingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	traceID := c.traceID
+	if id, ok := traceIDField(fields); ok {
+		traceID = id
+	}
+
+	if !c.sampler.sample(ent, traceID) {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// traceIDField procura o trace_id entre os campos da entrada
+func traceIDField(fields []zapcore.Field) (string, bool) {
+	for _, f := range fields {
+		if f.Key == TraceIDKey && f.Type == zapcore.StringType {
+			return f.String, f.String != ""
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,129 @@
+// Package tracer provê uma função de conveniência para construir o
+// TracerProvider do OpenTelemetry com o exporter escolhido na configuração
+package tracer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/exporters/zipkin"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Exporters suportados
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterZipkin = "zipkin"
+	ExporterStdout = "stdout"
+)
+
+// endpoints usados quando a configuração não informa um
+var defaultEndpoints = map[string]string{
+	ExporterOTLP:   "localhost:4317",
+	ExporterZipkin: "http://localhost:9411/api/v2/spans",
+}
+
+// Config contém o necessário para construir o TracerProvider
+type Config struct {
+	ServiceName string
+	Exporter    string  // none, otlp, zipkin ou stdout
+	Endpoint    string  // vazio usa o endpoint padrão do exporter
+	Probability float64 // fração dos traces iniciados aqui que são registrados
+}
+
+// Provider agrupa o TracerProvider e a função que envia os spans pendentes e
+// libera os recursos no desligamento
+type Provider struct {
+	trace.TracerProvider
+	Shutdown func(ctx context.Context) error
+}
+
+// New constrói o TracerProvider e o registra como global, junto do
+// propagador W3C (traceparent e baggage). Com o exporter none nenhum span é
+// registrado, mas o contexto de trace recebido continua sendo propagado
+func New(cfg Config) (Provider, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	exporter, err := newExporter(cfg)
+	if err != nil {
+		return Provider{}, err
+	}
+
+	if exporter == nil {
+		tp := trace.NewNoopTracerProvider()
+		otel.SetTracerProvider(tp)
+
+		return Provider{
+			TracerProvider: tp,
+			Shutdown:       func(context.Context) error { return nil },
+		}, nil
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		// respeita a decisão do cliente quando o trace vem de fora
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.Probability))),
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(cfg.ServiceName),
+		)),
+	)
+	otel.SetTracerProvider(tp)
+
+	return Provider{
+		TracerProvider: tp,
+		Shutdown:       tp.Shutdown,
+	}, nil
+}
+
+// newExporter constrói o exporter escolhido. Retorna nil para o exporter none
+func newExporter(cfg Config) (sdktrace.SpanExporter, error) {
+	name := strings.ToLower(cfg.Exporter)
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = defaultEndpoints[name]
+	}
+
+	switch name {
+	case ExporterNone, "":
+		return nil, nil
+
+	case ExporterOTLP:
+		exporter, err := otlptracegrpc.New(context.Background(),
+			otlptracegrpc.WithEndpoint(endpoint),
+			otlptracegrpc.WithInsecure(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("otlp exporter: %w", err)
+		}
+		return exporter, nil
+
+	case ExporterZipkin:
+		exporter, err := zipkin.New(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("zipkin exporter: %w", err)
+		}
+		return exporter, nil
+
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("stdout exporter: %w", err)
+		}
+		return exporter, nil
+
+	default:
+		return nil, fmt.Errorf("unknown exporter %q", cfg.Exporter)
+	}
+}
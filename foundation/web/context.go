@@ -18,6 +18,7 @@ type Values struct {
 	TraceID    string // um identificador unico para aquela requisição
 	Now        time.Time
 	StatusCode int
+	Route      string // padrão da rota que atendeu a requisição, como /users/:user_id
 }
 
 // GetValues retorna o valor atual do contexto
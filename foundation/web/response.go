@@ -2,8 +2,12 @@ package web
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 )
 
 // Respond converte um valor Go em JSON e responde a requisição ao cliente
@@ -21,7 +25,59 @@ func Respond(ctx context.Context, w http.ResponseWriter, data any, statusCode in
 		return err
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	return write(w, jsonData, "application/json", statusCode)
+}
+
+// RespondContentType funciona como Respond para formatos derivados do JSON,
+// como application/problem+json, informando o Content-Type da resposta
+func RespondContentType(ctx context.Context, w http.ResponseWriter, data any, contentType string, statusCode int) error {
+	SetStatusCode(ctx, statusCode)
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return write(w, jsonData, contentType, statusCode)
+}
+
+// RespondConditional funciona como Respond, mas deve ser usada em respostas de
+// uma única entidade. Calcula um ETag forte a partir do corpo da resposta e
+// informa o Last-Modified com base em lastModified (normalmente o DateUpdated
+// da entidade). Se o cliente já possuir a versão atual do recurso, informada
+// nos cabeçalhos If-None-Match ou If-Modified-Since, responde 304 Not Modified
+// sem corpo, evitando que o mesmo dado seja baixado novamente
+func RespondConditional(ctx context.Context, w http.ResponseWriter, r *http.Request, data any, lastModified time.Time, statusCode int) error {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	// o ETag forte é o hash do conteúdo exato que seria enviado, então qualquer
+	// mudança na representação gera um ETag diferente
+	etag := fmt.Sprintf(`"%x"`, sha256.Sum256(jsonData))
+	w.Header().Set("ETag", etag)
+
+	// o formato HTTP de datas possui precisão de segundos
+	lastModified = lastModified.UTC().Truncate(time.Second)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+	}
+
+	if statusCode == http.StatusOK && notModified(r, etag, lastModified) {
+		SetStatusCode(ctx, http.StatusNotModified)
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	SetStatusCode(ctx, statusCode)
+
+	return write(w, jsonData, "application/json", statusCode)
+}
+
+// write escreve o JSON já serializado na resposta
+func write(w http.ResponseWriter, jsonData []byte, contentType string, statusCode int) error {
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(statusCode)
 
 	if _, err := w.Write(jsonData); err != nil {
@@ -30,3 +86,40 @@ func Respond(ctx context.Context, w http.ResponseWriter, data any, statusCode in
 
 	return nil
 }
+
+// notModified avalia as pré-condições da requisição conforme a RFC 9110.
+// If-None-Match tem precedência, de forma que If-Modified-Since só é avaliado
+// quando o cliente não enviou um ETag
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatch(inm, etag)
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+		return !lastModified.After(t)
+	}
+
+	return false
+}
+
+// etagMatch verifica se algum dos ETags da lista enviada em If-None-Match
+// corresponde ao ETag atual. If-None-Match usa comparação fraca, então o
+// prefixo W/ é ignorado
+func etagMatch(header string, etag string) bool {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimSpace(v)
+		if v == "*" || strings.TrimPrefix(v, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,122 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// streamError indica que a resposta já havia começado a ser enviada quando o
+// erro aconteceu, então não há como responder o erro ao cliente
+type streamError struct {
+	err error
+}
+
+// Error implementação da interface error
+func (se *streamError) Error() string {
+	return fmt.Sprintf("stream: %s", se.err)
+}
+
+// Unwrap permite inspecionar o erro original com errors.Is e errors.As
+func (se *streamError) Unwrap() error {
+	return se.err
+}
+
+// IsStreamError checa se o erro aconteceu depois que a resposta já estava
+// sendo enviada
+func IsStreamError(err error) bool {
+	var se *streamError
+	return errors.As(err, &se)
+}
+
+// StreamErrorTrailer é o trailer enviado quando a resposta é interrompida
+// depois de começar. Como o status 200 já foi enviado, é por ele que o cliente
+// sabe que o conteúdo recebido está incompleto
+const StreamErrorTrailer = "Stream-Error"
+
+// Stream responde a requisição escrevendo o corpo aos poucos, sem montar a
+// resposta inteira em memória. Se filename for informado, o cliente é
+// instruído a salvar o conteúdo como arquivo. fn recebe o writer do corpo e
+// um flush que envia ao cliente o que já foi escrito.
+//
+// O status 200 só é enviado na primeira escrita, então um erro de fn antes
+// disso, como uma falha na query, é respondido normalmente. Depois da
+// primeira escrita, o erro é do tipo streamError e o trailer
+// StreamErrorTrailer é enviado para que o cliente não trate o conteúdo
+// truncado como completo
+func Stream(ctx context.Context, w http.ResponseWriter, contentType string, filename string, fn func(w io.Writer, flush func()) error) error {
+	sw := streamWriter{
+		ctx:         ctx,
+		w:           w,
+		contentType: contentType,
+		filename:    filename,
+	}
+
+	flush := func() {
+		if !sw.started {
+			return
+		}
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+	}
+
+	err := fn(&sw, flush)
+	if err == nil {
+		// o cliente pode ter desconectado depois da última linha
+		err = ctx.Err()
+	}
+
+	switch {
+	case err != nil && !sw.started:
+		return err
+	case err != nil:
+		w.Header().Set(StreamErrorTrailer, "incomplete response")
+		return &streamError{err: err}
+	}
+
+	// sem nenhuma escrita, a resposta é enviada vazia
+	sw.start()
+
+	return nil
+}
+
+// streamWriter adia o envio dos cabeçalhos e do status até a primeira escrita
+type streamWriter struct {
+	ctx         context.Context
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+// Write implementa io.Writer
+func (sw *streamWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	sw.start()
+
+	return sw.w.Write(p)
+}
+
+func (sw *streamWriter) start() {
+	if sw.started {
+		return
+	}
+	sw.started = true
+
+	h := sw.w.Header()
+	h.Set("Content-Type", sw.contentType)
+	if sw.filename != "" {
+		h.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": sw.filename}))
+	}
+	h.Set("Trailer", StreamErrorTrailer)
+
+	SetStatusCode(sw.ctx, http.StatusOK)
+	sw.w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,121 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startSpan inicia o span de uma requisição. O contexto de trace enviado pelo
+// cliente nos cabeçalhos W3C (traceparent) é usado como pai, e o contexto do
+// novo span é devolvido nos cabeçalhos da resposta
+func (a *App) startSpan(w http.ResponseWriter, r *http.Request, path string) (context.Context, trace.Span) {
+	propagator := otel.GetTextMapPropagator()
+
+	ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+	ctx, span := a.tracer.Start(ctx, r.Method+" "+path,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.HTTPMethod(r.Method),
+			semconv.HTTPRoute(path),
+			semconv.URLPath(r.URL.Path),
+		),
+	)
+
+	propagator.Inject(ctx, propagation.HeaderCarrier(w.Header()))
+
+	return ctx, span
+}
+
+// setSpanStatus registra o status da resposta no span da requisição
+func setSpanStatus(span trace.Span, statusCode int) {
+	if statusCode == 0 {
+		return
+	}
+
+	span.SetAttributes(semconv.HTTPStatusCode(statusCode))
+	if statusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(statusCode))
+	}
+}
+
+// traceID retorna o ID da requisição. Quando o App usa um cabeçalho próprio,
+// como X-Request-ID, o valor enviado é usado se for válido. Com traceparent,
+// ou sem o cabeçalho, o ID é o do trace do span, que já continua o trace do
+// cliente. Quando o span não é válido, por não haver um tracer configurado nem
+// traceparent na requisição, gera um ID aleatório
+func (a *App) traceID(r *http.Request, span trace.Span) string {
+	if a.traceIDHeader != "" && !strings.EqualFold(a.traceIDHeader, TraceParentHeader) {
+		if id := r.Header.Get(a.traceIDHeader); validTraceID(id) {
+			span.SetAttributes(attribute.String("http.request_id", id))
+			return id
+		}
+	}
+
+	if sc := span.SpanContext(); sc.HasTraceID() {
+		return sc.TraceID().String()
+	}
+	return uuid.NewString()
+}
+
+// responseTraceIDHeader é o cabeçalho em que o ID da requisição é devolvido.
+// Com traceparent o cabeçalho W3C da resposta descreve o span do servidor e
+// não existe quando não há trace, então o ID vai em X-Trace-ID
+func (a *App) responseTraceIDHeader() string {
+	if a.traceIDHeader == "" || strings.EqualFold(a.traceIDHeader, TraceParentHeader) {
+		return TraceIDHeader
+	}
+	return a.traceIDHeader
+}
+
+// maxTraceIDLen limita o tamanho dos IDs aceitos do cliente, já que eles são
+// repetidos em todos os logs da requisição
+const maxTraceIDLen = 128
+
+// validTraceID informa se o ID enviado pelo cliente pode ser usado. São aceitos
+// apenas caracteres comuns em IDs (letras, números, -, _, . e :) para que o
+// valor não quebre os logs nem os cabeçalhos da resposta
+func validTraceID(id string) bool {
+	if id == "" || len(id) > maxTraceIDLen {
+		return false
+	}
+
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// AddSpan cria um span filho do span atual do contexto. Usa o mesmo tracer
+// provider do span pai, então sem um trace em andamento o span não é
+// registrado em lugar nenhum
+func AddSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	tracer := trace.SpanFromContext(ctx).TracerProvider().Tracer(tracerName)
+
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// Cabeçalhos de onde o ID da requisição pode ser lido e em que é devolvido
+const (
+	RequestIDHeader   = "X-Request-ID"
+	TraceParentHeader = "traceparent"
+	TraceIDHeader     = "X-Trace-ID"
+)
+
+// nome usado para os spans criados fora do App
+const tracerName = "github.com/vitoraalmeida/service/foundation/web"
@@ -0,0 +1,84 @@
+package web_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/vitoraalmeida/service/foundation/web"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// trace e span enviados pelo cliente no cabeçalho traceparent
+const (
+	clientTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	clientSpanID  = "00f067aa0ba902b7"
+)
+
+func TestRequestSpan(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	app := web.NewApp(make(chan os.Signal, 1), tp.Tracer("test"), web.TraceParentHeader)
+	app.Handle(http.MethodGet, "/products/:product_id", func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		_, span := web.AddSpan(ctx, "child")
+		span.End()
+
+		return web.Respond(ctx, w, nil, http.StatusNoContent)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/products/1", nil)
+	r.Header.Set("traceparent", "00-"+clientTraceID+"-"+clientSpanID+"-01")
+	w := httptest.NewRecorder()
+
+	app.ServeHTTP(w, r)
+
+	spans := exporter.GetSpans()
+	server := findSpan(t, spans, "GET /products/:product_id")
+	child := findSpan(t, spans, "child")
+
+	if got := server.SpanContext.TraceID().String(); got != clientTraceID {
+		t.Errorf("server span trace id = %s, want %s", got, clientTraceID)
+	}
+	if got := server.Parent.SpanID().String(); got != clientSpanID || !server.Parent.IsRemote() {
+		t.Errorf("server span parent = %s (remote %t), want remote %s", got, server.Parent.IsRemote(), clientSpanID)
+	}
+
+	if child.Parent.SpanID() != server.SpanContext.SpanID() {
+		t.Errorf("child span parent = %s, want server span %s", child.Parent.SpanID(), server.SpanContext.SpanID())
+	}
+
+	want := "00-" + clientTraceID + "-" + server.SpanContext.SpanID().String() + "-01"
+	if got := w.Header().Get("traceparent"); got != want {
+		t.Errorf("response traceparent = %q, want %q", got, want)
+	}
+
+	if got := w.Header().Get(web.TraceIDHeader); got != clientTraceID {
+		t.Errorf("response %s = %q, want %q", web.TraceIDHeader, got, clientTraceID)
+	}
+}
+
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+
+	names := make([]string, len(spans))
+	for i, span := range spans {
+		names[i] = span.Name
+	}
+	t.Fatalf("span %q not found, got [%s]", name, strings.Join(names, ", "))
+
+	return tracetest.SpanStub{}
+}
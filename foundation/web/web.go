@@ -9,7 +9,7 @@ import (
 	"time"
 
 	"github.com/dimfeld/httptreemux/v5"
-	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Tipo que lida com http requests no nosso framework.
@@ -27,12 +27,20 @@ type App struct {
 	*httptreemux.ContextMux
 	shutdown chan os.Signal
 	mw       []Middleware
+	tracer   trace.Tracer // cria um span para cada requisição
+
+	// cabeçalho de onde o ID da requisição é lido, como X-Request-ID ou
+	// traceparent. Vazio usa sempre o ID do trace
+	traceIDHeader string
 }
 
 // Cria e retorna uma instância de App
 // Usar semântica de ponteiro quando estamos lidando com uma API, com algo
 // que deve compartilhar estados e recursos
-func NewApp(shutdown chan os.Signal, mw ...Middleware) *App {
+// O tracer pode ser obtido de um TracerProvider noop quando não houver
+// exportação de traces. traceIDHeader é o cabeçalho de onde o ID da requisição
+// é lido, permitindo relacionar os logs com os IDs gerados por um API gateway
+func NewApp(shutdown chan os.Signal, tracer trace.Tracer, traceIDHeader string, mw ...Middleware) *App {
 	// usando
 	return &App{
 		// httptreemux.NewContextMux() retorna um ponteiro para ContextMux
@@ -40,6 +48,9 @@ func NewApp(shutdown chan os.Signal, mw ...Middleware) *App {
 		ContextMux: httptreemux.NewContextMux(),
 		shutdown:   shutdown,
 		mw:         mw,
+		tracer:     tracer,
+
+		traceIDHeader: traceIDHeader,
 	}
 }
 
@@ -69,18 +80,30 @@ func (a *App) Handle(method string, path string, handler Handler, mw ...Middlewa
 		// pode executar qualquer código antes de chamar o handler final
 		// ex.: verificar autenticação, criar um log da requisição etc
 
+		// inicia o span da requisição, continuando o trace do cliente se
+		// ele tiver enviado o cabeçalho traceparent
+		ctx, span := a.startSpan(w, r, path)
+		defer span.End()
+
 		// cria os valores que serão passados no contexto da requisição
 		v := Values{
 			// As requisições terão um ID único para identificarmos
-			// todas as açẽos e passos que fizeram parte do processo
-			TraceID: uuid.NewString(),
+			// todas as açẽos e passos que fizeram parte do processo. É o
+			// ID enviado pelo cliente ou o mesmo ID do trace, para
+			// relacionar logs e spans
+			TraceID: a.traceID(r, span),
+			Route:   path,
 			// o tempo em que aquela requisição começou para compararmos
 			// quando finalizar e termos o tempo total que levou
 			Now: time.Now().UTC(),
 		}
 		// cria o contexto reaproveitando o contexto do request e adicionando nosso
 		// dado para os logs (primeiro middlware)
-		ctx := context.WithValue(r.Context(), key, &v)
+		ctx = context.WithValue(ctx, key, &v)
+
+		// o ID é devolvido antes de qualquer escrita na resposta, para que o
+		// cliente possa informá-lo ao suporte mesmo em caso de erro
+		w.Header().Set(a.responseTraceIDHeader(), v.TraceID)
 
 		// chama a cadeia de funções
 		// se temos um erro aqui quer dizer que nosso handler de erros retornou
@@ -96,6 +119,7 @@ func (a *App) Handle(method string, path string, handler Handler, mw ...Middlewa
 
 		// pode executar qualquer código depois do handler
 		// logs etc
+		setSpanStatus(span, v.StatusCode)
 	}
 
 	// como h tem a assinatura que o ContextMux.Handle espera, podemos usar